@@ -1,26 +1,113 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	grpcServer "shortbin/internal/server/grpc"
 	httpServer "shortbin/internal/server/http"
+	"shortbin/internal/storage"
+	sweeperService "shortbin/internal/sweeper/service"
+	"shortbin/migrations"
+	"shortbin/pkg/blocklist"
+	"shortbin/pkg/bloom"
 	"shortbin/pkg/config"
-	"shortbin/pkg/database"
+	"shortbin/pkg/idgen"
 	"shortbin/pkg/kafka"
 	"shortbin/pkg/logger"
+	"shortbin/pkg/migrate"
+	"shortbin/pkg/outbox"
 	"shortbin/pkg/redis"
+	"shortbin/pkg/sink"
 	"shortbin/pkg/validation"
 )
 
+// main serves the API, or runs the migrate subcommand
+//
+//	@title						shortbin API
+//	@version					1.0
+//	@description				URL shortener. The spec is generated from these annotations with go generate ./internal/docs
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Bearer access token, API keys go in the X-API-Key header instead
 func main() {
 	cfg := config.LoadConfig("config.yaml")
 	logger.Initialize(cfg.Environment)
 
-	db, err := database.NewDatabase(cfg.DataSourceName)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.Open(ctx, cfg.DataSourceName)
 	if err != nil {
 		logger.Fatal("Cannot connect to database ", err)
 	}
 
+	// Migrations, the click outbox and the postgres event sink need
+	// Postgres, db is nil with any other store
+	var db *pgxpool.Pool
+	if pg, ok := store.(*storage.Postgres); ok {
+		db = pg.Pool()
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if db == nil {
+			logger.Fatal("Migrations only apply to postgres, other stores create their schema on start")
+		}
+		runMigrate(ctx, db, os.Args[2:])
+		return
+	}
+
+	// Replicas starting together are serialised by the migration lock
+	if cfg.MigrateOnStart && db != nil {
+		migrator, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			logger.Fatal("Cannot load migrations ", err)
+		}
+		if _, err = migrator.Up(ctx, 0); err != nil {
+			logger.Fatal("Migration failed ", err)
+		}
+	}
+
+	linger := time.Duration(cfg.Kafka.Linger) * time.Millisecond
+
+	sinkCfg := sink.Config{
+		Type:         cfg.EventSink.Type,
+		Broker:       cfg.Kafka.Broker,
+		BatchSize:    cfg.Kafka.BatchSize,
+		BatchTimeout: linger,
+		FilePath:     cfg.EventSink.FilePath,
+	}
+	eventSink, err := sink.New(sinkCfg, db)
+	if err != nil {
+		logger.Fatal("Cannot create event sink ", err)
+	}
+
+	// Clicks that can not be written to Kafka are spooled to click_outbox
+	// and relayed once Kafka is reachable again
+	var spool kafka.Spool
+	if cfg.Outbox.Enabled && db != nil && (sinkCfg.Type == "" || sinkCfg.Type == sink.Kafka) {
+		spool = outbox.New(db)
+		relay := outbox.NewRelay(db, kafka.NewKafkaSink(cfg.Kafka.Broker, cfg.Kafka.BatchSize, linger), outbox.Config{
+			BatchSize:    cfg.Outbox.BatchSize,
+			PollInterval: cfg.Outbox.PollInterval * time.Second,
+		})
+		go relay.Run(ctx)
+	}
+
 	kp := kafka.NewKafkaProducer(kafka.Config{
-		Broker: cfg.Kafka.Broker,
+		Broker:    cfg.Kafka.Broker,
+		QueueSize: cfg.Kafka.QueueSize,
+		BatchSize: cfg.Kafka.BatchSize,
+		Linger:    linger,
+		Policy:    cfg.Kafka.Policy,
+		Spool:     spool,
+		Sink:      eventSink,
 	})
 
 	cache := redis.New(redis.Config{
@@ -29,10 +116,78 @@ func main() {
 		Database: cfg.Redis.Database,
 	})
 
-	validator := validation.New()
+	// Hot links are served from an in-process tier, deletes are broadcast
+	// so every instance drops its copy
+	if cfg.Redis.Local.Enabled {
+		cache, err = redis.NewTiered(ctx, cache, redis.TieredConfig{
+			Size:    cfg.Redis.Local.Size,
+			TTL:     cfg.Redis.Local.TTL * time.Second,
+			Channel: cfg.Redis.Local.Channel,
+		})
+		if err != nil {
+			logger.Fatal("Cannot subscribe to cache invalidations ", err)
+		}
+	}
+
+	idGen, err := idgen.New(idgen.Config{
+		Strategy:  cfg.IDGenerator.Strategy,
+		NodeID:    cfg.IDGenerator.NodeID,
+		MaxLength: cfg.ShortIDLength.Max,
+	})
+	if err != nil {
+		logger.Fatal("Cannot create id generator ", err)
+	}
+
+	// Lookups of unknown short IDs are answered from a Bloom filter of all
+	// existing IDs, seeded in the background on first start and again
+	// whenever Redis loses the bitmap
+	filter := bloom.Disabled()
+	if cfg.BloomFilter.Enabled {
+		redisFilter := bloom.New(cache, bloom.Config{
+			Key:    cfg.BloomFilter.Key,
+			Size:   cfg.BloomFilter.Size,
+			Hashes: cfg.BloomFilter.Hashes,
+		})
+		go redisFilter.Run(ctx, store.Retrieve().ListShortIDs)
+		filter = redisFilter
+	}
 
-	httpSvr := httpServer.NewServer(validator, db, kp, cache)
-	if err = httpSvr.Run(); err != nil {
+	if cfg.Sweeper.Enabled {
+		sweeper := sweeperService.NewSweeper(store.Sweeper(), cfg.Sweeper)
+		go sweeper.Run(ctx)
+	}
+
+	// Domains admins block in blocked_domains are cached and refreshed
+	blocked := blocklist.New(store.BlockedDomains)
+	if err = blocked.Load(ctx); err != nil {
+		logger.Fatal("Cannot load domain blocklist ", err)
+	}
+	go blocked.Run(ctx, cfg.LongURL.BlocklistRefresh*time.Second)
+
+	validator := validation.New(validation.WithBlocklist(blocked))
+
+	// The gRPC API shares the store, cache and producer the HTTP server
+	// closes on shutdown, so HTTP only shuts down once gRPC has drained
+	httpCtx := ctx
+	var grpcErr chan error
+	if cfg.GRPCPort > 0 {
+		var stopHTTP context.CancelFunc
+		httpCtx, stopHTTP = context.WithCancel(context.WithoutCancel(ctx))
+		grpcErr = make(chan error, 1)
+		grpcSvr := grpcServer.NewServer(validator, store, cache, idGen, filter)
+		go func() {
+			grpcErr <- grpcSvr.Run(ctx)
+			stopHTTP()
+		}()
+	}
+
+	httpSvr := httpServer.NewServer(validator, store, kp, cache, idGen, filter)
+	if err = httpSvr.Run(httpCtx); err != nil {
 		logger.Fatal(err)
 	}
+	if grpcErr != nil {
+		if err = <-grpcErr; err != nil {
+			logger.Fatal(err)
+		}
+	}
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"shortbin/migrations"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/migrate"
+)
+
+const migrateUsage = "usage: shortbin migrate up [n] | down [n] | status"
+
+// runMigrate runs the migrate subcommand, up applies all pending migrations
+// unless a count is given, down reverts the last one
+func runMigrate(ctx context.Context, db *pgxpool.Pool, args []string) {
+	if len(args) == 0 || len(args) > 2 {
+		logger.Fatal(migrateUsage)
+	}
+
+	n := 0
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+			logger.Fatal(migrateUsage)
+		}
+	}
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		logger.Fatal("Cannot load migrations ", err)
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx, n)
+		if err != nil {
+			logger.Fatal("Migration failed ", err)
+		}
+		logger.Infof("%d migrations applied", len(done))
+	case "down":
+		done, err := migrator.Down(ctx, n)
+		if err != nil {
+			logger.Fatal("Migration failed ", err)
+		}
+		logger.Infof("%d migrations reverted", len(done))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Fatal("Cannot read migration status ", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		logger.Fatal(migrateUsage)
+	}
+}
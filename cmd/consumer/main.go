@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"shortbin/internal/stats/consumer"
+	"shortbin/internal/storage"
+	"shortbin/pkg/config"
+	"shortbin/pkg/events"
+	"shortbin/pkg/kafka"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/sink"
+)
+
+const defaultConsumerGroup = "shortbin-stats"
+
+func main() {
+	cfg := config.LoadConfig("config.yaml")
+	logger.Initialize(cfg.Environment)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.Open(ctx, cfg.DataSourceName)
+	if err != nil {
+		logger.Fatal("Cannot connect to database ", err)
+	}
+	defer store.Close()
+
+	groupID := cfg.Kafka.ConsumerGroup
+	if groupID == "" {
+		groupID = defaultConsumerGroup
+	}
+
+	topics := []string{cfg.Kafka.ClicksTopic, cfg.Kafka.PublicClicksTopic}
+
+	// without a broker the API writes click events to click_events, which
+	// are consumed from there instead
+	var kc kafka.IKafkaConsumer
+	if cfg.EventSink.Type == sink.Postgres {
+		pg, ok := store.(*storage.Postgres)
+		if !ok {
+			logger.Fatal("The postgres event sink needs a postgres data source")
+		}
+		kc = sink.NewPostgresConsumer(pg.Pool(), topics)
+	} else {
+		kc = kafka.NewKafkaConsumer(kafka.ConsumerConfig{
+			Broker:  cfg.Kafka.Broker,
+			GroupID: groupID,
+			Topics:  topics,
+		})
+	}
+	defer func() {
+		if err := kc.Close(); err != nil {
+			logger.Error("Failed to close kafka consumer ", err)
+		}
+	}()
+
+	clickConsumer := consumer.NewClickConsumer(store.Clicks())
+
+	logger.Info("Consuming click events from ", cfg.Kafka.ClicksTopic, " and ", cfg.Kafka.PublicClicksTopic)
+	handler := kafka.TypedHandler(
+		clickConsumer.Handle,
+		kafka.JSONEncoder[*events.ClickEvent]{},
+		kafka.ProtoEncoder[events.ClickEvent, *events.ClickEvent]{},
+	)
+	if err = kc.Consume(ctx, handler); err != nil {
+		logger.Error("Consumer stopped ", err)
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+)
+
+type CreateReq struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes,omitempty" validate:"omitempty,dive,oneof=create stats manage"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// CreateRes carries the key itself, it is only ever returned here
+type CreateRes struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type ListRes struct {
+	APIKeys []APIKey `json:"api_keys"`
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/apikeys/dto"
+	"shortbin/internal/apikeys/service"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+	"shortbin/pkg/utils"
+	"shortbin/pkg/validation"
+)
+
+type APIKeysHandler struct {
+	validator validation.Validation
+	service   service.IAPIKeysService
+}
+
+func NewAPIKeysHandler(validator validation.Validation, service service.IAPIKeysService) *APIKeysHandler {
+	return &APIKeysHandler{
+		validator: validator,
+		service:   service,
+	}
+}
+
+// Create godoc
+//
+//	@Summary	Create an API key, the key is only returned once
+//	@Tags		api-keys
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateReq	true	"Body"
+//	@Success	201	{object}	dto.CreateRes
+//	@Failure	400	{object}	response.ErrorResponse	"invalid parameters"
+//	@Router		/api/v1/api-keys [post]
+func (h *APIKeysHandler) Create(c *gin.Context) {
+	var req dto.CreateReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body ", err)
+		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
+		return
+	}
+
+	// validated here as well to tell invalid parameters from failures
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
+		return
+	}
+
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	apiKey, key, err := h.service.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	var res dto.CreateRes
+	utils.Copy(&res.APIKey, &apiKey)
+	res.Key = key
+	response.JSON(c, http.StatusCreated, res)
+}
+
+// List godoc
+//
+//	@Summary	List my API keys
+//	@Tags		api-keys
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListRes
+//	@Router		/api/v1/api-keys [get]
+func (h *APIKeysHandler) List(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	apiKeys, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	res := dto.ListRes{APIKeys: make([]dto.APIKey, 0, len(apiKeys))}
+	if len(apiKeys) > 0 {
+		utils.Copy(&res.APIKeys, &apiKeys)
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// Delete godoc
+//
+//	@Summary	Revoke one of my API keys
+//	@Tags		api-keys
+//	@Security	ApiKeyAuth
+//	@Param		id	path	string	true	"API key ID"
+//	@Success	204
+//	@Failure	404	{object}	response.ErrorResponse	"id not found"
+//	@Router		/api/v1/api-keys/{id} [delete]
+func (h *APIKeysHandler) Delete(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), userID, c.Param("id")); err != nil {
+		if err.Error() == response.IDNotFound {
+			response.Error(c, http.StatusNotFound, err, response.IDNotFound)
+			return
+		}
+
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
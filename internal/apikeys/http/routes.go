@@ -0,0 +1,26 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/apikeys/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/middleware"
+	"shortbin/pkg/validation"
+)
+
+func Routes(r *gin.RouterGroup, store storage.Store, validator validation.Validation) {
+	apiKeysRepo := store.APIKeys()
+	apiKeysSvc := service.NewAPIKeysService(validator, apiKeysRepo)
+	apiKeysHandler := NewAPIKeysHandler(validator, apiKeysSvc)
+
+	// keys are managed with access tokens only, so that a key cannot mint
+	// another one with more scopes than its own
+	authMiddleware := middleware.JWTAuth(nil)
+	apiKeysRoute := r.Group("/api-keys", authMiddleware)
+	{
+		apiKeysRoute.POST("", apiKeysHandler.Create)
+		apiKeysRoute.GET("", apiKeysHandler.List)
+		apiKeysRoute.DELETE("/:id", apiKeysHandler.Delete)
+	}
+}
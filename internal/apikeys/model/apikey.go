@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// APIKey model, only the SHA-256 hash of the key is stored. Prefix is the
+// public part of the key shown to identify it.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	HashedKey  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"` // *time.Time as it can be null
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/apikeys/model"
+	"shortbin/pkg/response"
+)
+
+type IAPIKeysRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	List(ctx context.Context, userID string) ([]*model.APIKey, error)
+	Delete(ctx context.Context, userID string, id string) error
+	GetByHash(ctx context.Context, hashedKey string) (*model.APIKey, error)
+	Touch(ctx context.Context, id string) error
+}
+
+type APIKeysRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeysRepository(db *pgxpool.Pool) *APIKeysRepo {
+	return &APIKeysRepo{db: db}
+}
+
+func (r *APIKeysRepo) Create(ctx context.Context, key *model.APIKey) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.Create", "repository")
+	defer rootSpan.End()
+
+	query := `INSERT INTO api_keys (user_id, name, prefix, hashed_key, scopes) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+
+	return r.db.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.HashedKey, key.Scopes).Scan(&key.ID, &key.CreatedAt)
+}
+
+func (r *APIKeysRepo) List(ctx context.Context, userID string) ([]*model.APIKey, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.List", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT id, user_id, name, prefix, scopes, created_at, last_used_at FROM api_keys WHERE user_id=$1 ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.APIKey, error) {
+		var key model.APIKey
+		err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scopes, &key.CreatedAt, &key.LastUsedAt)
+		return &key, err
+	})
+}
+
+func (r *APIKeysRepo) Delete(ctx context.Context, userID string, id string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.Delete", "repository")
+	defer rootSpan.End()
+
+	query := `DELETE FROM api_keys WHERE id=$1 AND user_id=$2`
+
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(response.IDNotFound)
+	}
+
+	return nil
+}
+
+func (r *APIKeysRepo) GetByHash(ctx context.Context, hashedKey string) (*model.APIKey, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.GetByHash", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT id, user_id, name, prefix, scopes, created_at, last_used_at FROM api_keys WHERE hashed_key=$1`
+
+	var key model.APIKey
+	err := r.db.QueryRow(ctx, query, hashedKey).Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scopes, &key.CreatedAt, &key.LastUsedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(response.InvalidAPIKey)
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// Touch records that the key was just used
+func (r *APIKeysRepo) Touch(ctx context.Context, id string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.Touch", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE api_keys SET last_used_at=now() WHERE id=$1`
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
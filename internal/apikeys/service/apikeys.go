@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/module/apmzap/v2"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/apikeys/dto"
+	"shortbin/internal/apikeys/model"
+	"shortbin/internal/apikeys/repository"
+	"shortbin/pkg/idgen"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/middleware"
+	"shortbin/pkg/response"
+	"shortbin/pkg/validation"
+)
+
+const (
+	// keys look like sb_<prefix>_<secret>
+	keyPrefix    = "sb_"
+	prefixLength = 8
+	secretLength = 32
+
+	// last_used_at is only written once per interval to keep busy keys
+	// from turning every request into a write
+	touchInterval = time.Minute
+)
+
+var allScopes = []string{middleware.ScopeCreate, middleware.ScopeStats, middleware.ScopeManage}
+
+//go:generate mockery --name=IAPIKeysService
+type IAPIKeysService interface {
+	Create(ctx context.Context, userID string, req *dto.CreateReq) (*model.APIKey, string, error)
+	List(ctx context.Context, userID string) ([]*model.APIKey, error)
+	Delete(ctx context.Context, userID string, id string) error
+	ValidateAPIKey(ctx context.Context, key string) (string, []string, error)
+}
+
+type APIKeysService struct {
+	validator validation.Validation
+	repo      repository.IAPIKeysRepository
+	random    idgen.IDGenerator
+}
+
+func NewAPIKeysService(
+	validator validation.Validation,
+	repo repository.IAPIKeysRepository) *APIKeysService {
+	return &APIKeysService{
+		validator: validator,
+		repo:      repo,
+		random:    idgen.NewRandom(),
+	}
+}
+
+// Create a key for the user, it is returned in full only here. Keys are
+// created with every scope unless scopes are given.
+func (s *APIKeysService) Create(ctx context.Context, userID string, req *dto.CreateReq) (*model.APIKey, string, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, "", err
+	}
+
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysService.Create", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	prefix, err := s.random.Generate(prefixLength)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := s.random.Generate(secretLength)
+	if err != nil {
+		return nil, "", err
+	}
+	key := keyPrefix + prefix + "_" + secret
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = allScopes
+	}
+
+	apiKey := model.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    keyPrefix + prefix,
+		HashedKey: hashKey(key),
+		Scopes:    scopes,
+	}
+	if err = s.repo.Create(ctx, &apiKey); err != nil {
+		logger.Infof("APIKeys.Create fail, userID: %s, error: %s", userID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, "", err
+	}
+
+	return &apiKey, key, nil
+}
+
+func (s *APIKeysService) List(ctx context.Context, userID string) ([]*model.APIKey, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysService.List", "service")
+	defer rootSpan.End()
+
+	return s.repo.List(ctx, userID)
+}
+
+func (s *APIKeysService) Delete(ctx context.Context, userID string, id string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysService.Delete", "service")
+	defer rootSpan.End()
+
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// ValidateAPIKey returns the user and scopes of key and records its use
+func (s *APIKeysService) ValidateAPIKey(ctx context.Context, key string) (string, []string, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysService.ValidateAPIKey", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	if !strings.HasPrefix(key, keyPrefix) {
+		return "", nil, errors.New(response.InvalidAPIKey)
+	}
+
+	apiKey, err := s.repo.GetByHash(ctx, hashKey(key))
+	if err != nil {
+		if err.Error() != response.InvalidAPIKey {
+			logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		}
+		return "", nil, err
+	}
+
+	if apiKey.LastUsedAt == nil || time.Since(*apiKey.LastUsedAt) > touchInterval {
+		if err = s.repo.Touch(ctx, apiKey.ID); err != nil {
+			logger.Infof("failed to record api key use, id: %s, error: %s", apiKey.ID, err)
+			logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		}
+	}
+
+	return apiKey.UserID, apiKey.Scopes, nil
+}
+
+// hashKey of an API key. Keys are long and random, so an unsalted fast hash
+// is enough and lets them be looked up by hash.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,11 +5,10 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 
 	"shortbin/internal/auth/dto"
 	"shortbin/internal/auth/service"
+	"shortbin/pkg/database"
 	"shortbin/pkg/jwt"
 	"shortbin/pkg/logger"
 	"shortbin/pkg/response"
@@ -42,7 +41,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := h.service.Login(c, &req)
+	user, accessToken, refreshToken, err := h.service.Login(c.Request.Context(), &req)
 	if err != nil {
 		logger.Error("Failed to login ", err)
 		response.Error(c, http.StatusBadRequest, err, response.WrongCredentials)
@@ -72,11 +71,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.Register(c, &req)
+	user, err := h.service.Register(c.Request.Context(), &req)
 	if err != nil {
 		// Check for user already exists error
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if database.IsUniqueViolation(err) {
 			response.Error(c, http.StatusConflict, err, response.UserAlreadyExists)
 			return
 		}
@@ -91,14 +89,8 @@ func (h *UserHandler) Register(c *gin.Context) {
 	response.JSON(c, http.StatusOK, res)
 }
 
-// GetMe godoc
-//
-//	@Summary	get my profile
-//	@Tags		users
-//	@Security	ApiKeyAuth
-//	@Produce	json
-//	@Success	200	{object}	dto.User
-//	@Router		/api/v1/auth/me [get]
+// GetMe returns the profile of the authenticated user. It is not routed, so
+// it carries no annotations for the spec.
 func (h *UserHandler) GetMe(c *gin.Context) {
 	userID := c.GetString("userId")
 	if userID == "" {
@@ -106,7 +98,7 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.GetUserByID(c, userID)
+	user, err := h.service.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
@@ -120,7 +112,7 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 
 // RefreshToken godoc
 //
-//	@Summary	changes the password
+//	@Summary	Refresh the access token
 //	@Tags		users
 //	@Security	ApiKeyAuth
 //	@Produce	json
@@ -134,7 +126,7 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 	}
 
 	userEmail := c.GetString("userEmail")
-	accessToken, err := h.service.RefreshToken(c, userID, userEmail)
+	accessToken, err := h.service.RefreshToken(c.Request.Context(), userID, userEmail)
 	if err != nil {
 		logger.Error("Failed to refresh token ", err)
 		response.Error(c, http.StatusUnauthorized, err, response.Unauthorized)
@@ -152,7 +144,7 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 //	@Security	ApiKeyAuth
 //	@Produce	json
 //	@Param		_	body	dto.ChangePasswordReq	true	"Body"
-//	@Router		/api/v1/auth/change-password [put]
+//	@Router		/api/v1/auth/change-password [post]
 func (h *UserHandler) ChangePassword(c *gin.Context) {
 	var req dto.ChangePasswordReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
@@ -162,7 +154,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	}
 
 	userID := c.GetString("userId")
-	err := h.service.ChangePassword(c, userID, &req)
+	err := h.service.ChangePassword(c.Request.Context(), userID, &req)
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
@@ -189,10 +181,10 @@ func (h *UserHandler) ForgotPassword(c *gin.Context) {
 	}
 
 	// accessToken is temporary and should be sent over email
-	accessToken, err := h.service.SendPasswordResetEmail(c, &req)
+	accessToken, err := h.service.SendPasswordResetEmail(c.Request.Context(), &req)
 	if err != nil {
 		// check if error is that userID not found
-		if !errors.Is(err, pgx.ErrNoRows) {
+		if !database.IsNotFound(err) {
 			logger.Error(err)
 			response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
 		}
@@ -228,7 +220,7 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 	}
 
 	userID := payload["id"].(string)
-	err = h.service.ResetPassword(c, userID, req.Password)
+	err = h.service.ResetPassword(c.Request.Context(), userID, req.Password)
 
 	if err != nil {
 		logger.Error(err.Error())
@@ -2,27 +2,29 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 
-	"shortbin/internal/auth/repository"
 	"shortbin/internal/auth/service"
+	"shortbin/internal/storage"
 	"shortbin/pkg/middleware"
+	"shortbin/pkg/ratelimit"
 	"shortbin/pkg/validation"
 )
 
-func Routes(r *gin.RouterGroup, dbPool *pgxpool.Pool, validator validation.Validation) {
-	userRepo := repository.NewUserRepository(dbPool)
+func Routes(r *gin.RouterGroup, store storage.Store, validator validation.Validation, limiter *ratelimit.Limiter) {
+	userRepo := store.Users()
 	userSvc := service.NewUserService(validator, userRepo)
 	userHandler := NewUserHandler(userSvc)
 
-	authMiddleware := middleware.JWTAuth()
+	// changing the password requires an access token, API keys are refused
+	authMiddleware := middleware.JWTAuth(nil)
 	refreshAuthMiddleware := middleware.JWTRefresh()
+	rateLimitMiddleware := ratelimit.Middleware(limiter, "auth")
 	authRoute := r.Group("/auth")
 	{
-		authRoute.POST("/register", userHandler.Register)
-		authRoute.POST("/login", userHandler.Login)
-		authRoute.POST("/forgot-password", userHandler.ForgotPassword)
-		authRoute.POST("/reset-password", userHandler.ResetPassword)
+		authRoute.POST("/register", rateLimitMiddleware, userHandler.Register)
+		authRoute.POST("/login", rateLimitMiddleware, userHandler.Login)
+		authRoute.POST("/forgot-password", rateLimitMiddleware, userHandler.ForgotPassword)
+		authRoute.POST("/reset-password", rateLimitMiddleware, userHandler.ResetPassword)
 		authRoute.POST("/change-password", authMiddleware, userHandler.ChangePassword)
 		authRoute.POST("/refresh", refreshAuthMiddleware, userHandler.RefreshToken)
 	}
@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.elastic.co/apm/v2"
 
@@ -9,11 +10,11 @@ import (
 )
 
 type IUserRepository interface {
-	Create(ctx *gin.Context, email string, hashedPassword string) (*model.User, error)
-	Update(ctx *gin.Context, user *model.User) error
-	UpdatePassword(ctx *gin.Context, userID string, hashedPassword string) error
-	GetUserByID(ctx *gin.Context, userID string) (*model.User, error)
-	GetUserByEmail(ctx *gin.Context, email string) (*model.User, error)
+	Create(ctx context.Context, email string, hashedPassword string) (*model.User, error)
+	Update(ctx context.Context, user *model.User) error
+	UpdatePassword(ctx context.Context, userID string, hashedPassword string) error
+	GetUserByID(ctx context.Context, userID string) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
 }
 
 type UserRepo struct {
@@ -24,9 +25,8 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepo {
 	return &UserRepo{db: db}
 }
 
-func (r *UserRepo) Create(ctx *gin.Context, email string, hashedPassword string) (*model.User, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserRepo.Create", "repository", nil)
+func (r *UserRepo) Create(ctx context.Context, email string, hashedPassword string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.Create", "repository")
 	defer rootSpan.End()
 
 	query := `INSERT INTO users (email, hashed_password) VALUES ($1, $2) RETURNING id, created_at, email, hashed_password`
@@ -39,9 +39,8 @@ func (r *UserRepo) Create(ctx *gin.Context, email string, hashedPassword string)
 	return &createdUser, nil
 }
 
-func (r *UserRepo) Update(ctx *gin.Context, user *model.User) error {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserRepo.Update", "repository", nil)
+func (r *UserRepo) Update(ctx context.Context, user *model.User) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.Update", "repository")
 	defer rootSpan.End()
 
 	query := `UPDATE users SET email=$1, hashed_password=$2 WHERE id=$3`
@@ -49,9 +48,8 @@ func (r *UserRepo) Update(ctx *gin.Context, user *model.User) error {
 	return err
 }
 
-func (r *UserRepo) UpdatePassword(ctx *gin.Context, userID string, hashedPassword string) error {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserRepo.UpdatePassword", "repository", nil)
+func (r *UserRepo) UpdatePassword(ctx context.Context, userID string, hashedPassword string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.UpdatePassword", "repository")
 	defer rootSpan.End()
 
 	query := `UPDATE users SET hashed_password=$1 WHERE id=$2`
@@ -59,9 +57,8 @@ func (r *UserRepo) UpdatePassword(ctx *gin.Context, userID string, hashedPasswor
 	return err
 }
 
-func (r *UserRepo) GetUserByID(ctx *gin.Context, id string) (*model.User, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserRepo.GetUserByID", "repository", nil)
+func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.GetUserByID", "repository")
 	defer rootSpan.End()
 
 	query := `SELECT id, created_at, email, hashed_password FROM users WHERE id=$1`
@@ -74,9 +71,8 @@ func (r *UserRepo) GetUserByID(ctx *gin.Context, id string) (*model.User, error)
 	return &user, nil
 }
 
-func (r *UserRepo) GetUserByEmail(ctx *gin.Context, email string) (*model.User, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserRepo.GetUserByEmail", "repository", nil)
+func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.GetUserByEmail", "repository")
 	defer rootSpan.End()
 
 	query := `SELECT id, created_at, email, hashed_password FROM users WHERE email=$1`
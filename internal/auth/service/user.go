@@ -1,10 +1,8 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"go.elastic.co/apm/module/apmzap/v2"
 	"go.elastic.co/apm/v2"
 	"golang.org/x/crypto/bcrypt"
@@ -12,6 +10,7 @@ import (
 	"shortbin/internal/auth/dto"
 	"shortbin/internal/auth/model"
 	"shortbin/internal/auth/repository"
+	"shortbin/pkg/database"
 	"shortbin/pkg/jwt"
 	"shortbin/pkg/logger"
 	"shortbin/pkg/utils"
@@ -20,13 +19,13 @@ import (
 
 //go:generate mockery --name=IUserService
 type IUserService interface {
-	Login(ctx *gin.Context, req *dto.LoginReq) (*model.User, string, string, error)
-	Register(ctx *gin.Context, req *dto.RegisterReq) (*model.User, error)
-	GetUserByID(ctx *gin.Context, id string) (*model.User, error)
-	RefreshToken(ctx *gin.Context, userID string, userEmail string) (string, error)
-	ChangePassword(ctx *gin.Context, userID string, req *dto.ChangePasswordReq) error
-	SendPasswordResetEmail(ctx *gin.Context, req *dto.ForgotPasswordReq) (string, error)
-	ResetPassword(c *gin.Context, userID string, password string) error
+	Login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error)
+	Register(ctx context.Context, req *dto.RegisterReq) (*model.User, error)
+	GetUserByID(ctx context.Context, id string) (*model.User, error)
+	RefreshToken(ctx context.Context, userID string, userEmail string) (string, error)
+	ChangePassword(ctx context.Context, userID string, req *dto.ChangePasswordReq) error
+	SendPasswordResetEmail(ctx context.Context, req *dto.ForgotPasswordReq) (string, error)
+	ResetPassword(ctx context.Context, userID string, password string) error
 }
 
 type UserService struct {
@@ -43,14 +42,13 @@ func NewUserService(
 	}
 }
 
-func (s *UserService) Login(ctx *gin.Context, req *dto.LoginReq) (*model.User, string, string, error) {
+func (s *UserService) Login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error) {
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return nil, "", "", err
 	}
 
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.Login", "service", nil)
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserService.Login", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
 	user, err := s.repo.GetUserByEmail(ctx, req.Email)
@@ -73,22 +71,20 @@ func (s *UserService) Login(ctx *gin.Context, req *dto.LoginReq) (*model.User, s
 	return user, accessToken, refreshToken, nil
 }
 
-func (s *UserService) Register(ctx *gin.Context, req *dto.RegisterReq) (*model.User, error) {
+func (s *UserService) Register(ctx context.Context, req *dto.RegisterReq) (*model.User, error) {
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return nil, err
 	}
 
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.Register", "service", nil)
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserService.Register", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
 	hashedPassword := utils.HashAndSalt([]byte(req.Password))
 	user, err := s.repo.Create(ctx, req.Email, hashedPassword)
 	if err != nil {
 		// Check if the error indicates that the user already exists
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique violation code
+		if database.IsUniqueViolation(err) {
 			return nil, err
 		}
 		logger.Infof("Register.Create fail, email: %s, error: %s", req.Email, err)
@@ -99,10 +95,9 @@ func (s *UserService) Register(ctx *gin.Context, req *dto.RegisterReq) (*model.U
 	return user, nil
 }
 
-func (s *UserService) GetUserByID(ctx *gin.Context, userID string) (*model.User, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.GetUserByID", "service", nil)
+func (s *UserService) GetUserByID(ctx context.Context, userID string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserService.GetUserByID", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
 	user, err := s.repo.GetUserByID(ctx, userID)
@@ -115,9 +110,8 @@ func (s *UserService) GetUserByID(ctx *gin.Context, userID string) (*model.User,
 	return user, nil
 }
 
-func (s *UserService) RefreshToken(ctx *gin.Context, userID string, userEmail string) (string, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.RefreshToken", "service", nil)
+func (s *UserService) RefreshToken(ctx context.Context, userID string, userEmail string) (string, error) {
+	rootSpan, _ := apm.StartSpan(ctx, "*UserService.RefreshToken", "service")
 	defer rootSpan.End()
 
 	tokenData := map[string]interface{}{
@@ -128,14 +122,13 @@ func (s *UserService) RefreshToken(ctx *gin.Context, userID string, userEmail st
 	return accessToken, nil
 }
 
-func (s *UserService) ChangePassword(ctx *gin.Context, userID string, req *dto.ChangePasswordReq) error {
+func (s *UserService) ChangePassword(ctx context.Context, userID string, req *dto.ChangePasswordReq) error {
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return err
 	}
 
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.ChangePassword", "service", nil)
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserService.ChangePassword", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
 	if req.Password == req.NewPassword {
@@ -165,19 +158,18 @@ func (s *UserService) ChangePassword(ctx *gin.Context, userID string, req *dto.C
 	return nil
 }
 
-func (s *UserService) SendPasswordResetEmail(ctx *gin.Context, req *dto.ForgotPasswordReq) (string, error) {
+func (s *UserService) SendPasswordResetEmail(ctx context.Context, req *dto.ForgotPasswordReq) (string, error) {
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return "", err
 	}
 
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.SendPasswordResetEmail", "service", nil)
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserService.SendPasswordResetEmail", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
 	user, err := s.repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) { // user not found
+		if database.IsNotFound(err) { // user not found
 			return "", err
 		}
 
@@ -194,10 +186,9 @@ func (s *UserService) SendPasswordResetEmail(ctx *gin.Context, req *dto.ForgotPa
 	return accessToken, nil
 }
 
-func (s *UserService) ResetPassword(ctx *gin.Context, userID string, password string) error {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*UserService.ResetPassword", "service", nil)
+func (s *UserService) ResetPassword(ctx context.Context, userID string, password string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserService.ResetPassword", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
 	hashedPassword := utils.HashAndSalt([]byte(password))
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// CachedURLVersion is bumped whenever CachedURL changes incompatibly, entries
+// of any other version are treated as cache misses
+const CachedURLVersion = 1
+
+// CachedURL is the Redis cache entry of a short ID. NotFound entries cache
+// the absence of a short ID and carry no other fields.
+type CachedURL struct {
+	Version      int       `json:"v"`
+	NotFound     bool      `json:"not_found,omitempty"`
+	UserID       string    `json:"user_id,omitempty"` // "-1" for anonymous links
+	LongURL      string    `json:"long_url,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RedirectType int       `json:"redirect_type,omitempty"`
+	Protected    bool      `json:"protected,omitempty"`
+	// RefreshAt is when the entry is due to be read again from the database
+	// and Delta how long that took, used for early probabilistic refresh
+	RefreshAt time.Time     `json:"refresh_at,omitempty"`
+	Delta     time.Duration `json:"delta,omitempty"`
+}
+
+// RefreshTTL keeps NotFound entries to the short expiry they were written
+// with, so that they are not extended by every lookup of an unknown ID, and
+// never extends a link entry beyond the link's own expiry
+func (u *CachedURL) RefreshTTL(ttl time.Duration) time.Duration {
+	if u.NotFound {
+		return 0
+	}
+	return min(ttl, time.Until(u.ExpiresAt))
+}
+
+// NewCachedURL from a URL row
+func NewCachedURL(url *URL) *CachedURL {
+	userID := "-1"
+	if url.UserID != nil {
+		userID = *url.UserID
+	}
+
+	return &CachedURL{
+		Version:      CachedURLVersion,
+		UserID:       userID,
+		LongURL:      url.LongURL,
+		ExpiresAt:    url.ExpiresAt,
+		RedirectType: url.RedirectType,
+		Protected:    url.HashedPassword != nil,
+	}
+}
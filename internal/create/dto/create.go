@@ -5,13 +5,33 @@ import (
 )
 
 type CreateReq struct {
-	LongURL   string     `json:"long_url"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	LongURL      string     `json:"long_url" validate:"required,longurl=RequestHost"`
+	CustomAlias  string     `json:"custom_alias,omitempty"`
+	RedirectType int        `json:"redirect_type,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	Password     string     `json:"password,omitempty" validate:"omitempty,password"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	// RequestHost is the host the request was served on, set by the handler
+	RequestHost string `json:"-"`
+}
+
+// BulkItemRes is the outcome of the item at Index of a bulk request, either
+// Link or Error is set
+type BulkItemRes struct {
+	Index int        `json:"index"`
+	Link  *CreateRes `json:"link,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+type BulkRes struct {
+	Created int           `json:"created"`
+	Failed  int           `json:"failed"`
+	Items   []BulkItemRes `json:"items"`
 }
 
 type CreateRes struct {
-	ShortID   string    `json:"short_id"`
-	LongURL   string    `json:"long_url"`
-	CreatedAt time.Time `json:"created_at"`
-	ExpiresAt time.Time `json:"expires_at"`
+	ShortID      string    `json:"short_id"`
+	LongURL      string    `json:"long_url"`
+	RedirectType int       `json:"redirect_type"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
 }
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"shortbin/internal/create/dto"
+	"shortbin/pkg/response"
+)
+
+// csvColumns of a bulk create CSV, the header row names the columns used and
+// only long_url is required. expires_at is RFC 3339.
+var csvColumns = map[string]struct{}{
+	"long_url":      {},
+	"custom_alias":  {},
+	"redirect_type": {},
+	"password":      {},
+	"expires_at":    {},
+}
+
+// parseCSV reads bulk create items from r, stopping after max items
+func parseCSV(r io.Reader, max int) ([]*dto.CreateReq, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := csvColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["long_url"]; !ok {
+		return nil, errors.New("csv has no long_url column")
+	}
+
+	var reqs []*dto.CreateReq
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return reqs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(reqs) == max {
+			return nil, errors.New(response.TooManyItems)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		req := dto.CreateReq{
+			LongURL:     field("long_url"),
+			CustomAlias: field("custom_alias"),
+			Password:    field("password"),
+		}
+		if v := field("redirect_type"); v != "" {
+			if req.RedirectType, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid redirect_type %q", line, v)
+			}
+		}
+		if v := field("expires_at"); v != "" {
+			expiresAt, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid expires_at %q", line, v)
+			}
+			req.ExpiresAt = &expiresAt
+		}
+
+		reqs = append(reqs, &req)
+	}
+}
@@ -1,17 +1,23 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"shortbin/internal/create/dto"
 	"shortbin/internal/create/service"
+	"shortbin/pkg/config"
+	"shortbin/pkg/database"
 	"shortbin/pkg/logger"
 	"shortbin/pkg/response"
 	"shortbin/pkg/utils"
+	"shortbin/pkg/validation"
 )
 
+const defaultBulkCreateMax = 1000
+
 type CreateHandler struct {
 	service service.ICreateService
 }
@@ -22,6 +28,18 @@ func NewUserHandler(service service.ICreateService) *CreateHandler {
 	}
 }
 
+// Create godoc
+//
+//	@Summary	Create a short URL, optionally with a custom alias
+//	@Tags		urls
+//	@Produce	json
+//	@Param		_	body		dto.CreateReq	true	"Body"
+//	@Success	200	{object}	dto.CreateRes
+//	@Failure	400	{object}	response.ErrorResponse	"invalid parameters, invalid alias or expiry exceeds plan limit"
+//	@Failure	403	{object}	response.ErrorResponse	"feature not included in plan"
+//	@Failure	409	{object}	response.ErrorResponse	"alias already exists"
+//	@Failure	429	{object}	response.ErrorResponse	"link quota exceeded"
+//	@Router		/api/v1/create [post]
 func (h CreateHandler) Create(c *gin.Context) {
 	var req dto.CreateReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
@@ -29,10 +47,33 @@ func (h CreateHandler) Create(c *gin.Context) {
 		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
 		return
 	}
+	req.RequestHost = c.Request.Host
 
 	userID := c.GetString("userId")
-	url, err := h.service.Create(c, userID, &req)
+	url, err := h.service.Create(c.Request.Context(), userID, &req)
 	if err != nil {
+		// Check for alias already taken error
+		if database.IsUniqueViolation(err) && req.CustomAlias != "" {
+			response.Error(c, http.StatusConflict, err, response.AliasAlreadyExists)
+			return
+		}
+		if validation.IsInvalid(err) {
+			response.Invalid(c, err)
+			return
+		}
+
+		switch e := err.Error(); e {
+		case response.IDLengthNotInRange, response.InvalidAlias, response.AliasReserved, response.ExpiryNotInPlan:
+			response.Error(c, http.StatusBadRequest, err, e)
+			return
+		case response.FeatureNotInPlan:
+			response.Error(c, http.StatusForbidden, err, e)
+			return
+		case response.QuotaExceeded:
+			response.Error(c, http.StatusTooManyRequests, err, e)
+			return
+		}
+
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
 		return
@@ -42,3 +83,113 @@ func (h CreateHandler) Create(c *gin.Context) {
 	utils.Copy(&res, &url)
 	response.JSON(c, http.StatusOK, res)
 }
+
+// CreateBulk godoc
+//
+//	@Summary	Create up to bulk_create_max short URLs at once
+//	@Description	Takes a JSON array of create requests, or a CSV with a header row naming the columns long_url, custom_alias, redirect_type, password and expires_at, either as the text/csv body or as the file field of a multipart form. Every item is reported on individually.
+//	@Tags		urls
+//	@Security	ApiKeyAuth
+//	@Accept		json
+//	@Accept		text/csv
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		_		body		[]dto.CreateReq	false	"Body"
+//	@Param		file	formData	file			false	"CSV file"
+//	@Success	200		{object}	dto.BulkRes
+//	@Failure	400		{object}	response.ErrorResponse	"invalid parameters or too many items"
+//	@Failure	429		{object}	response.ErrorResponse	"link quota exceeded"
+//	@Router		/api/v1/create/bulk [post]
+func (h CreateHandler) CreateBulk(c *gin.Context) {
+	maxItems := config.GetConfig().BulkCreateMax
+	if maxItems <= 0 {
+		maxItems = defaultBulkCreateMax
+	}
+
+	reqs, err := bindBulk(c, maxItems)
+	if err != nil {
+		logger.Error("Failed to get bulk items ", err)
+		if err.Error() == response.TooManyItems {
+			response.Error(c, http.StatusBadRequest, err, response.TooManyItems)
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
+		return
+	}
+	for _, req := range reqs {
+		req.RequestHost = c.Request.Host
+	}
+
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	urls, errs, err := h.service.CreateBulk(c.Request.Context(), userID, reqs)
+	if err != nil {
+		if err.Error() == response.QuotaExceeded {
+			response.Error(c, http.StatusTooManyRequests, err, response.QuotaExceeded)
+			return
+		}
+
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	res := dto.BulkRes{Items: make([]dto.BulkItemRes, len(reqs))}
+	for i := range reqs {
+		res.Items[i].Index = i
+		if errs[i] != nil {
+			res.Items[i].Error = errs[i].Error()
+			res.Failed++
+			continue
+		}
+
+		var link dto.CreateRes
+		utils.Copy(&link, &urls[i])
+		res.Items[i].Link = &link
+		res.Created++
+	}
+
+	response.JSON(c, http.StatusOK, res)
+}
+
+// bindBulk reads the bulk items from a JSON array, a CSV body or a CSV file
+// uploaded as the file field of a multipart form
+func bindBulk(c *gin.Context, maxItems int) ([]*dto.CreateReq, error) {
+	if c.Request.Body == nil {
+		return nil, errors.New(response.InvalidParameters)
+	}
+
+	switch c.ContentType() {
+	case "text/csv":
+		return parseCSV(c.Request.Body, maxItems)
+	case "multipart/form-data":
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return parseCSV(file, maxItems)
+	default:
+		var reqs []*dto.CreateReq
+		if err := c.ShouldBindJSON(&reqs); err != nil {
+			return nil, err
+		}
+		if len(reqs) > maxItems {
+			return nil, errors.New(response.TooManyItems)
+		}
+		for _, req := range reqs {
+			if req == nil {
+				return nil, errors.New(response.InvalidParameters)
+			}
+		}
+		return reqs, nil
+	}
+}
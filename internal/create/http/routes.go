@@ -2,19 +2,29 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 
-	"shortbin/internal/create/repository"
 	"shortbin/internal/create/service"
+	plansService "shortbin/internal/plans/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/bloom"
+	"shortbin/pkg/idgen"
 	"shortbin/pkg/middleware"
+	"shortbin/pkg/ratelimit"
+	"shortbin/pkg/redis"
 	"shortbin/pkg/validation"
 )
 
-func Routes(r *gin.RouterGroup, dbPool *pgxpool.Pool, validator validation.Validation) {
-	createRepo := repository.NewCreateRepository(dbPool)
-	createSvc := service.NewCreateService(validator, createRepo)
+func Routes(r *gin.RouterGroup, store storage.Store, validator validation.Validation, idGen idgen.IDGenerator, cache redis.IRedis, filter bloom.Filter, limiter *ratelimit.Limiter, apiKeys middleware.APIKeyValidator) {
+	createRepo := store.Create()
+	plansSvc := plansService.NewPlansService(store.Plans())
+	createSvc := service.NewCreateService(validator, createRepo, idGen, cache, filter, plansSvc)
 	userHandler := NewUserHandler(createSvc)
 
-	authMiddleware := middleware.OptionalJWTAuth()
-	r.POST("/create", authMiddleware, userHandler.Create)
+	authMiddleware := middleware.OptionalJWTAuth(apiKeys)
+	rateLimitMiddleware := ratelimit.Middleware(limiter, "create")
+	scopeMiddleware := middleware.RequireScope(middleware.ScopeCreate)
+	r.POST("/create", authMiddleware, scopeMiddleware, rateLimitMiddleware, userHandler.Create)
+	// a bulk request takes a single rate limit token for up to bulk_create_max
+	// links, so it is only open to users, whose links count against their quota
+	r.POST("/create/bulk", middleware.JWTAuth(apiKeys), scopeMiddleware, rateLimitMiddleware, userHandler.CreateBulk)
 }
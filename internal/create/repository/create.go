@@ -1,7 +1,10 @@
 package repository
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.elastic.co/apm/v2"
 
@@ -9,7 +12,8 @@ import (
 )
 
 type ICreateRepository interface {
-	Create(ctx *gin.Context, url *model.URL) error
+	Create(ctx context.Context, url *model.URL) error
+	CreateBatch(ctx context.Context, urls []*model.URL) ([]bool, error)
 }
 
 type CreateRepo struct {
@@ -20,13 +24,44 @@ func NewCreateRepository(db *pgxpool.Pool) *CreateRepo {
 	return &CreateRepo{db: db}
 }
 
-func (r *CreateRepo) Create(ctx *gin.Context, url *model.URL) error {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*CreateRepo.Create", "repository", nil)
+func (r *CreateRepo) Create(ctx context.Context, url *model.URL) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*CreateRepo.Create", "repository")
 	defer rootSpan.End()
 
-	query := `INSERT INTO urls (short_id, long_url, user_id, created_at, expires_at) VALUES ($1, $2, $3, $4, $5)`
+	query := `INSERT INTO urls (short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
-	_, err := r.db.Exec(ctx, query, url.ShortID, url.LongURL, url.UserID, url.CreatedAt, url.ExpiresAt)
+	_, err := r.db.Exec(ctx, query, url.ShortID, url.LongURL, url.UserID, url.RedirectType, url.HashedPassword, url.CreatedAt, url.ExpiresAt)
 	return err
 }
+
+// CreateBatch inserts urls in one round trip. Instead of failing, a url whose
+// short ID is taken is skipped and reported as false at its index.
+func (r *CreateRepo) CreateBatch(ctx context.Context, urls []*model.URL) ([]bool, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*CreateRepo.CreateBatch", "repository")
+	defer rootSpan.End()
+
+	query := `INSERT INTO urls (short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (short_id) DO NOTHING RETURNING short_id`
+
+	batch := &pgx.Batch{}
+	for _, url := range urls {
+		batch.Queue(query, url.ShortID, url.LongURL, url.UserID, url.RedirectType, url.HashedPassword, url.CreatedAt, url.ExpiresAt)
+	}
+
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	inserted := make([]bool, len(urls))
+	for i := range urls {
+		var shortID string
+		if err := results.QueryRow().Scan(&shortID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+		inserted[i] = true
+	}
+
+	return inserted, results.Close()
+}
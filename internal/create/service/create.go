@@ -1,52 +1,295 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"go.elastic.co/apm/module/apmzap/v2"
 	"go.elastic.co/apm/v2"
 
 	"shortbin/internal/common/model"
 	"shortbin/internal/create/dto"
 	"shortbin/internal/create/repository"
+	plansModel "shortbin/internal/plans/model"
+	plansService "shortbin/internal/plans/service"
+	"shortbin/pkg/bloom"
 	"shortbin/pkg/config"
+	"shortbin/pkg/database"
+	"shortbin/pkg/idgen"
 	"shortbin/pkg/logger"
+	"shortbin/pkg/redis"
+	"shortbin/pkg/response"
 	"shortbin/pkg/utils"
 	"shortbin/pkg/validation"
 )
 
+const defaultMaxRetries = 3
+
+const aliasChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz-_"
+
+// reservedAliases can not be used as custom aliases as they clash with
+// routes served from the root of the engine.
+var reservedAliases = map[string]struct{}{
+	"api":     {},
+	"health":  {},
+	"debug":   {},
+	"admin":   {},
+	"login":   {},
+	"logout":  {},
+	"static":  {},
+	"assets":  {},
+	"favicon": {},
+}
+
 //go:generate mockery --name=ICreateService
 type ICreateService interface {
-	Create(ctx *gin.Context, id string, req *dto.CreateReq) (*model.URL, error)
+	Create(ctx context.Context, id string, req *dto.CreateReq) (*model.URL, error)
+	CreateBulk(ctx context.Context, id string, reqs []*dto.CreateReq) ([]*model.URL, []error, error)
 }
 
 type CreateService struct {
 	validator validation.Validation
 	repo      repository.ICreateRepository
+	idGen     idgen.IDGenerator
+	redis     redis.IRedis
+	filter    bloom.Filter
+	plans     plansService.IPlansService
 }
 
 func NewCreateService(
 	validator validation.Validation,
-	repo repository.ICreateRepository) *CreateService {
+	repo repository.ICreateRepository,
+	idGen idgen.IDGenerator,
+	redis redis.IRedis,
+	filter bloom.Filter,
+	plans plansService.IPlansService) *CreateService {
 	return &CreateService{
 		validator: validator,
 		repo:      repo,
+		idGen:     idGen,
+		redis:     redis,
+		filter:    filter,
+		plans:     plans,
 	}
 }
 
-func (s *CreateService) Create(ctx *gin.Context, id string, req *dto.CreateReq) (*model.URL, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	traceContextFields := apmzap.TraceContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*CreateService.Create", "service", nil)
+func (s *CreateService) Create(ctx context.Context, id string, req *dto.CreateReq) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*CreateService.Create", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
 	defer rootSpan.End()
 
-	if err := s.validator.ValidateStruct(req); err != nil {
+	if err := s.validator.ValidateStructCtx(ctx, req); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.plans.GetPlan(ctx, id)
+	if err != nil {
+		logger.Infof("Create.GetPlan failed, userID: %s, error: %s", id, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, err
+	}
+
+	url, err := newURL(id, plan, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// anonymous links are not counted, they are only rate limited
+	if id != "" {
+		if err = s.plans.Reserve(ctx, id, plan, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CustomAlias != "" {
+		url.ShortID = req.CustomAlias
+		if err = s.filter.Add(url.ShortID); err == nil {
+			err = s.repo.Create(ctx, url)
+		}
+	} else {
+		err = s.createWithGeneratedID(ctx, url)
+	}
+
+	if err != nil {
+		if id != "" {
+			s.plans.Release(ctx, id, 1)
+		}
+		logger.Infof("Create.Create failed, long_url: %s, error: %s", url.LongURL, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
 		return nil, err
 	}
 
+	s.announce(ctx, url.ShortID)
+	return url, nil
+}
+
+// CreateBulk creates a link for each of reqs. Items are validated one by one,
+// with the hosts of their long URLs looked up up front, and inserted in a
+// single batch; the failure of an item is reported at its
+// index in the returned errors and does not affect the others. Quota is
+// reserved for all valid items up front, and given back for those that fail
+// to insert.
+func (s *CreateService) CreateBulk(ctx context.Context, id string, reqs []*dto.CreateReq) ([]*model.URL, []error, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*CreateService.CreateBulk", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	plan, err := s.plans.GetPlan(ctx, id)
+	if err != nil {
+		logger.Infof("CreateBulk.GetPlan failed, userID: %s, error: %s", id, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, nil, err
+	}
+
+	longURLs := make([]string, len(reqs))
+	for i, req := range reqs {
+		longURLs[i] = req.LongURL
+	}
+	validateCtx := validation.ResolveHosts(ctx, longURLs)
+
+	urls := make([]*model.URL, len(reqs))
+	errs := make([]error, len(reqs))
+	aliases := make(map[string]struct{})
+	var valid int
+	for i, req := range reqs {
+		if errs[i] = s.validator.ValidateStructCtx(validateCtx, req); errs[i] != nil {
+			continue
+		}
+		if urls[i], errs[i] = newURL(id, plan, req); errs[i] != nil {
+			continue
+		}
+
+		if req.CustomAlias != "" {
+			if _, taken := aliases[req.CustomAlias]; taken {
+				urls[i], errs[i] = nil, errors.New(response.AliasAlreadyExists)
+				continue
+			}
+			aliases[req.CustomAlias] = struct{}{}
+			urls[i].ShortID = req.CustomAlias
+		}
+		valid++
+	}
+
+	if valid == 0 {
+		return urls, errs, nil
+	}
+
+	if id != "" {
+		if err = s.plans.Reserve(ctx, id, plan, valid); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	created, err := s.createBatch(ctx, reqs, urls, errs)
+	if err != nil {
+		if id != "" {
+			s.plans.Release(ctx, id, valid)
+		}
+		logger.Infof("CreateBulk.CreateBatch failed, userID: %s, error: %s", id, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, nil, err
+	}
+
+	if failed := valid - len(created); failed > 0 && id != "" {
+		s.plans.Release(ctx, id, failed)
+	}
+
+	if len(created) > 0 {
+		s.announce(ctx, created...)
+	}
+
+	return urls, errs, nil
+}
+
+// createBatch inserts the urls without an error, regenerating the IDs that
+// collided like createWithGeneratedID does. Custom aliases that are taken are
+// reported in errs. It returns the short IDs that were created.
+func (s *CreateService) createBatch(ctx context.Context, reqs []*dto.CreateReq, urls []*model.URL, errs []error) ([]string, error) {
+	cfg := config.GetConfig()
+
+	maxRetries := cfg.IDGenerator.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	var pending []int
+	for i := range urls {
+		if errs[i] == nil {
+			pending = append(pending, i)
+		}
+	}
+
+	created := make([]string, 0, len(pending))
+	length := cfg.ShortIDLength.Default
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 1 && length < cfg.ShortIDLength.Max {
+			length++
+		}
+
+		batch := make([]*model.URL, len(pending))
+		shortIDs := make([]string, len(pending))
+		for j, i := range pending {
+			if reqs[i].CustomAlias == "" {
+				shortID, generatedLength, err := s.generateID(ctx, length)
+				if err != nil {
+					return nil, err
+				}
+				urls[i].ShortID, length = shortID, generatedLength
+			}
+			batch[j] = urls[i]
+			shortIDs[j] = urls[i].ShortID
+		}
+
+		if err := s.filter.Add(shortIDs...); err != nil {
+			return nil, err
+		}
+
+		inserted, err := s.repo.CreateBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		var collided []int
+		for j, i := range pending {
+			switch {
+			case inserted[j]:
+				created = append(created, urls[i].ShortID)
+			case reqs[i].CustomAlias != "":
+				urls[i], errs[i] = nil, errors.New(response.AliasAlreadyExists)
+			case attempt >= maxRetries:
+				urls[i], errs[i] = nil, errors.New(response.SomethingWentWrong)
+			default:
+				collided = append(collided, i)
+			}
+		}
+
+		if len(collided) > 0 {
+			logger.Infof("short id collisions in batch, count: %d, attempt: %d", len(collided), attempt+1)
+		}
+		pending = collided
+	}
+
+	return created, nil
+}
+
+// newURL builds the link requested by req within the limits of plan
+func newURL(id string, plan *plansModel.Plan, req *dto.CreateReq) (*model.URL, error) {
+	if (req.CustomAlias != "" && !plan.CustomAliases) || (req.Password != "" && !plan.PasswordLinks) {
+		return nil, errors.New(response.FeatureNotInPlan)
+	}
+
+	if req.CustomAlias != "" {
+		if err := validateAlias(req.CustomAlias); err != nil {
+			return nil, err
+		}
+	}
+
 	var url model.URL
 	utils.Copy(&url, &req)
+	url.LongURL, _ = validation.NormalizeLongURL(req.LongURL) // already validated
 	url.CreatedAt = time.Now()
 	if url.ExpiresAt.IsZero() {
 		url.ExpiresAt = url.CreatedAt.AddDate(
@@ -54,22 +297,126 @@ func (s *CreateService) Create(ctx *gin.Context, id string, req *dto.CreateReq)
 			0,
 			0,
 		)
+		// the default expiry is shortened to what the plan allows
+		if maxExpiry := plan.MaxExpiry(url.CreatedAt); !maxExpiry.IsZero() && url.ExpiresAt.After(maxExpiry) {
+			url.ExpiresAt = maxExpiry
+		}
+	} else if maxExpiry := plan.MaxExpiry(url.CreatedAt); !maxExpiry.IsZero() && url.ExpiresAt.After(maxExpiry) {
+		return nil, errors.New(response.ExpiryNotInPlan)
 	}
 
-	idGenSpan := apmTx.StartSpan("utils.IdGenerator", "utils", nil)
-	url.ShortID = utils.IDGenerator(config.GetConfig().ShortIDLength.Default)
-	idGenSpan.End()
+	if url.RedirectType == 0 {
+		url.RedirectType = defaultRedirectType()
+	}
+
+	if req.Password != "" {
+		hashedPassword := utils.HashAndSalt([]byte(req.Password))
+		url.HashedPassword = &hashedPassword
+	}
 
 	if url.UserID = &id; id == "" {
 		url.UserID = nil
 	}
 
-	err := s.repo.Create(ctx, &url)
-	if err != nil {
-		logger.Infof("Create.Create failed, long_url: %s, error: %s", url.LongURL, err)
+	return &url, nil
+}
+
+// announce makes newly created short IDs resolvable. They may have been
+// looked up before they existed, so their negative cache entries are dropped
+// before the links are handed out. IDs are added to the Bloom filter before
+// they are inserted instead: a link missing from the filter would never be
+// found, so failing to add it fails the create while nothing is written yet.
+func (s *CreateService) announce(ctx context.Context, shortIDs ...string) {
+	traceContextFields := apmzap.TraceContext(ctx)
+
+	if err := s.redis.Delete(shortIDs...); err != nil {
+		logger.Infof("failed to evict cache, short_ids: %d, error: %s", len(shortIDs), err)
 		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
-		return nil, err
 	}
+}
 
-	return &url, nil
+// createWithGeneratedID inserts the url under a generated short ID, retrying
+// with a fresh ID on unique violation. After the first retry the length grows
+// by one per attempt up to ShortIDLength.Max.
+func (s *CreateService) createWithGeneratedID(ctx context.Context, url *model.URL) error {
+	cfg := config.GetConfig()
+
+	maxRetries := cfg.IDGenerator.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	length := cfg.ShortIDLength.Default
+	for attempt := 0; ; attempt++ {
+		if attempt > 1 && length < cfg.ShortIDLength.Max {
+			length++
+		}
+
+		shortID, generatedLength, err := s.generateID(ctx, length)
+		if err != nil {
+			return err
+		}
+		length = generatedLength
+
+		url.ShortID = shortID
+		if err = s.filter.Add(shortID); err != nil {
+			return err
+		}
+
+		err = s.repo.Create(ctx, url)
+		if err == nil || !database.IsUniqueViolation(err) || attempt >= maxRetries {
+			return err
+		}
+
+		logger.Infof("short id collision, short_id: %s, attempt: %d", shortID, attempt+1)
+	}
+}
+
+// generateID generates a short ID of length, or as much longer up to
+// ShortIDLength.Max as the generator needs, as snowflake IDs do not fit in
+// fewer than 10 characters. It returns the ID and its length.
+func (s *CreateService) generateID(ctx context.Context, length int) (string, int, error) {
+	idGenSpan, _ := apm.StartSpan(ctx, "idgen.Generate", "utils")
+	defer idGenSpan.End()
+
+	maxLength := config.GetConfig().ShortIDLength.Max
+	for {
+		shortID, err := s.idGen.Generate(length)
+		if errors.Is(err, idgen.ErrLengthTooShort) && length < maxLength {
+			length++
+			continue
+		}
+		return shortID, length, err
+	}
+}
+
+// defaultRedirectType is the configured redirect status code, falling back to
+// 301 when unset or not one of the supported redirect codes
+func defaultRedirectType() int {
+	switch redirectType := config.GetConfig().RedirectType; redirectType {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return redirectType
+	default:
+		return http.StatusMovedPermanently
+	}
+}
+
+func validateAlias(alias string) error {
+	cfg := config.GetConfig()
+
+	if length := len(alias); length < cfg.ShortIDLength.Min || cfg.ShortIDLength.Max < length {
+		return errors.New(response.IDLengthNotInRange)
+	}
+
+	for _, ch := range alias {
+		if !strings.ContainsRune(aliasChars, ch) {
+			return errors.New(response.InvalidAlias)
+		}
+	}
+
+	if _, ok := reservedAliases[strings.ToLower(alias)]; ok {
+		return errors.New(response.AliasReserved)
+	}
+
+	return nil
 }
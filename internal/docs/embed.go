@@ -0,0 +1,13 @@
+// Package docs holds the OpenAPI spec of the HTTP API, generated from the
+// swag annotations of the handlers, and the Swagger UI initializer loading
+// it. Regenerate the spec with go generate after changing an annotation.
+package docs
+
+import (
+	"embed"
+)
+
+//go:generate go -C ../../tools/openapi run . -root ../.. -o ../../internal/docs/openapi.json
+
+//go:embed openapi.json swagger-initializer.js
+var FS embed.FS
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"shortbin/internal/docs"
+)
+
+// Routes serves the OpenAPI spec at /api/docs/openapi.json, and Swagger UI
+// showing it at /api/docs
+func Routes(e *gin.Engine) {
+	// the spec and the initializer pointing Swagger UI at it take
+	// precedence over the files of the Swagger UI distribution
+	spec := http.FileServer(http.FS(docs.FS))
+	ui := http.FileServer(http.FS(swaggerFiles.FS))
+
+	e.GET("/api/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/api/docs/")
+	})
+	e.GET("/api/docs/*filepath", func(c *gin.Context) {
+		path := c.Param("filepath")
+		if _, err := fs.Stat(docs.FS, path[1:]); err == nil {
+			http.StripPrefix("/api/docs", spec).ServeHTTP(c.Writer, c.Request)
+			return
+		}
+		http.StripPrefix("/api/docs", ui).ServeHTTP(c.Writer, c.Request)
+	})
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+)
+
+type Link struct {
+	ShortID      string    `json:"short_id"`
+	LongURL      string    `json:"long_url"`
+	RedirectType int       `json:"redirect_type"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type ListReq struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ListRes struct {
+	Links      []Link `json:"links"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type UpdateReq struct {
+	LongURL      *string    `json:"long_url,omitempty" validate:"omitempty,longurl=RequestHost"`
+	RedirectType *int       `json:"redirect_type,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	// RequestHost is the host the request was served on, set by the handler
+	RequestHost string `json:"-"`
+}
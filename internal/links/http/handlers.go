@@ -0,0 +1,183 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/links/dto"
+	"shortbin/internal/links/service"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+	"shortbin/pkg/utils"
+	"shortbin/pkg/validation"
+)
+
+type LinksHandler struct {
+	service service.ILinksService
+}
+
+func NewLinksHandler(service service.ILinksService) *LinksHandler {
+	return &LinksHandler{
+		service: service,
+	}
+}
+
+// List godoc
+//
+//	@Summary	List my links
+//	@Tags		links
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+//	@Param		limit	query		int		false	"Page size, at most 100"
+//	@Success	200		{object}	dto.ListRes
+//	@Router		/api/v1/links [get]
+func (h *LinksHandler) List(c *gin.Context) {
+	var req dto.ListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query ", err)
+		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
+		return
+	}
+
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	urls, nextCursor, err := h.service.List(c.Request.Context(), userID, &req)
+	if err != nil {
+		if validation.IsInvalid(err) {
+			response.Invalid(c, err)
+			return
+		}
+		if err.Error() == response.InvalidCursor {
+			response.Error(c, http.StatusBadRequest, err, response.InvalidCursor)
+			return
+		}
+
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	res := dto.ListRes{Links: make([]dto.Link, 0, len(urls)), NextCursor: nextCursor}
+	if len(urls) > 0 {
+		utils.Copy(&res.Links, &urls)
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+// Get godoc
+//
+//	@Summary	Get one of my links
+//	@Tags		links
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		short_id	path		string	true	"Short ID"
+//	@Success	200			{object}	dto.Link
+//	@Failure	404			{object}	response.ErrorResponse	"id not found"
+//	@Router		/api/v1/links/{short_id} [get]
+func (h *LinksHandler) Get(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	url, err := h.service.Get(c.Request.Context(), userID, c.Param("short_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var res dto.Link
+	utils.Copy(&res, &url)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// Update godoc
+//
+//	@Summary	Update the long URL or expiry of one of my links
+//	@Tags		links
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		short_id	path		string			true	"Short ID"
+//	@Param		_			body		dto.UpdateReq	true	"Body"
+//	@Success	200			{object}	dto.Link
+//	@Failure	400			{object}	response.ErrorResponse	"invalid parameters or expiry exceeds plan limit"
+//	@Failure	404			{object}	response.ErrorResponse	"id not found"
+//	@Router		/api/v1/links/{short_id} [patch]
+func (h *LinksHandler) Update(c *gin.Context) {
+	var req dto.UpdateReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body ", err)
+		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
+		return
+	}
+
+	req.RequestHost = c.Request.Host
+
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	url, err := h.service.Update(c.Request.Context(), userID, c.Param("short_id"), &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var res dto.Link
+	utils.Copy(&res, &url)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// Delete godoc
+//
+//	@Summary	Delete one of my links
+//	@Tags		links
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		short_id	path	string	true	"Short ID"
+//	@Success	204
+//	@Failure	404	{object}	response.ErrorResponse	"id not found"
+//	@Router		/api/v1/links/{short_id} [delete]
+func (h *LinksHandler) Delete(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), userID, c.Param("short_id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func handleError(c *gin.Context, err error) {
+	if validation.IsInvalid(err) {
+		response.Invalid(c, err)
+		return
+	}
+
+	switch e := err.Error(); e {
+	case response.IDNotFound:
+		response.Error(c, http.StatusNotFound, err, response.IDNotFound)
+		return
+	case response.ExpiryNotInPlan:
+		response.Error(c, http.StatusBadRequest, err, e)
+		return
+	}
+
+	logger.Error(err.Error())
+	response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/links/service"
+	plansService "shortbin/internal/plans/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/middleware"
+	"shortbin/pkg/redis"
+	"shortbin/pkg/validation"
+)
+
+func Routes(r *gin.RouterGroup, store storage.Store, validator validation.Validation, cache redis.IRedis, apiKeys middleware.APIKeyValidator) {
+	linksRepo := store.Links()
+	plansSvc := plansService.NewPlansService(store.Plans())
+	linksSvc := service.NewLinksService(validator, linksRepo, cache, plansSvc)
+	linksHandler := NewLinksHandler(linksSvc)
+
+	authMiddleware := middleware.JWTAuth(apiKeys)
+	linksRoute := r.Group("/links", authMiddleware, middleware.RequireScope(middleware.ScopeManage))
+	{
+		linksRoute.GET("", linksHandler.List)
+		linksRoute.GET("/:short_id", linksHandler.Get)
+		linksRoute.PATCH("/:short_id", linksHandler.Update)
+		linksRoute.DELETE("/:short_id", linksHandler.Delete)
+	}
+}
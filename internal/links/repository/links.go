@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/common/model"
+	statsRepository "shortbin/internal/stats/repository"
+	"shortbin/pkg/response"
+)
+
+type ILinksRepository interface {
+	List(ctx context.Context, userID string, after *Cursor, limit int) ([]*model.URL, error)
+	GetByID(ctx context.Context, userID string, shortID string) (*model.URL, error)
+	Update(ctx context.Context, url *model.URL) error
+	Delete(ctx context.Context, userID string, shortID string) error
+}
+
+// Cursor is the position of the last link of a page, links are ordered by
+// (created_at, short_id) descending
+type Cursor struct {
+	CreatedAt time.Time
+	ShortID   string
+}
+
+type LinksRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLinksRepository(db *pgxpool.Pool) *LinksRepo {
+	return &LinksRepo{db: db}
+}
+
+func (r *LinksRepo) List(ctx context.Context, userID string, after *Cursor, limit int) ([]*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.List", "repository")
+	defer rootSpan.End()
+
+	var rows pgx.Rows
+	var err error
+	if after == nil {
+		query := `SELECT short_id, long_url, user_id, redirect_type, created_at, expires_at FROM urls
+			WHERE user_id=$1 ORDER BY created_at DESC, short_id DESC LIMIT $2`
+		rows, err = r.db.Query(ctx, query, userID, limit)
+	} else {
+		query := `SELECT short_id, long_url, user_id, redirect_type, created_at, expires_at FROM urls
+			WHERE user_id=$1 AND (created_at, short_id) < ($2, $3)
+			ORDER BY created_at DESC, short_id DESC LIMIT $4`
+		rows, err = r.db.Query(ctx, query, userID, after.CreatedAt, after.ShortID, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*model.URL
+	for rows.Next() {
+		var url model.URL
+		if err = rows.Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.RedirectType, &url.CreatedAt, &url.ExpiresAt); err != nil {
+			return nil, err
+		}
+		urls = append(urls, &url)
+	}
+
+	return urls, rows.Err()
+}
+
+func (r *LinksRepo) GetByID(ctx context.Context, userID string, shortID string) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.GetByID", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT short_id, long_url, user_id, redirect_type, created_at, expires_at FROM urls WHERE short_id=$1 AND user_id=$2`
+
+	var url model.URL
+	if err := r.db.QueryRow(ctx, query, shortID, userID).Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.RedirectType, &url.CreatedAt, &url.ExpiresAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(response.IDNotFound)
+		}
+		return nil, err
+	}
+
+	return &url, nil
+}
+
+func (r *LinksRepo) Update(ctx context.Context, url *model.URL) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.Update", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE urls SET long_url=$1, redirect_type=$2, expires_at=$3 WHERE short_id=$4 AND user_id=$5`
+	tag, err := r.db.Exec(ctx, query, url.LongURL, url.RedirectType, url.ExpiresAt, url.ShortID, url.UserID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(response.IDNotFound)
+	}
+
+	return nil
+}
+
+func (r *LinksRepo) Delete(ctx context.Context, userID string, shortID string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.Delete", "repository")
+	defer rootSpan.End()
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM urls WHERE short_id=$1 AND user_id=$2`
+		tag, err := tx.Exec(ctx, query, shortID, userID)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return errors.New(response.IDNotFound)
+		}
+
+		return statsRepository.DeleteStats(ctx, tx, []string{shortID})
+	})
+}
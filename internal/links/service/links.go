@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/module/apmzap/v2"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/common/model"
+	"shortbin/internal/links/dto"
+	"shortbin/internal/links/repository"
+	plansService "shortbin/internal/plans/service"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/redis"
+	"shortbin/pkg/response"
+	"shortbin/pkg/validation"
+)
+
+const defaultPageSize = 20
+
+//go:generate mockery --name=ILinksService
+type ILinksService interface {
+	List(ctx context.Context, userID string, req *dto.ListReq) ([]*model.URL, string, error)
+	Get(ctx context.Context, userID string, shortID string) (*model.URL, error)
+	Update(ctx context.Context, userID string, shortID string, req *dto.UpdateReq) (*model.URL, error)
+	Delete(ctx context.Context, userID string, shortID string) error
+}
+
+type LinksService struct {
+	validator validation.Validation
+	repo      repository.ILinksRepository
+	redis     redis.IRedis
+	plans     plansService.IPlansService
+}
+
+func NewLinksService(
+	validator validation.Validation,
+	repo repository.ILinksRepository,
+	redis redis.IRedis,
+	plans plansService.IPlansService) *LinksService {
+	return &LinksService{
+		validator: validator,
+		repo:      repo,
+		redis:     redis,
+		plans:     plans,
+	}
+}
+
+func (s *LinksService) List(ctx context.Context, userID string, req *dto.ListReq) ([]*model.URL, string, error) {
+	if err := s.validator.ValidateStructCtx(ctx, req); err != nil {
+		return nil, "", err
+	}
+
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksService.List", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	var after *repository.Cursor
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, "", errors.New(response.InvalidCursor)
+		}
+		after = cursor
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+
+	// fetch one extra link to find out whether there is a next page
+	urls, err := s.repo.List(ctx, userID, after, limit+1)
+	if err != nil {
+		logger.Infof("List.List fail, userID: %s, error: %s", userID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(urls) > limit {
+		urls = urls[:limit]
+		last := urls[limit-1]
+		nextCursor = encodeCursor(&repository.Cursor{CreatedAt: last.CreatedAt, ShortID: last.ShortID})
+	}
+
+	return urls, nextCursor, nil
+}
+
+func (s *LinksService) Get(ctx context.Context, userID string, shortID string) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksService.Get", "service")
+	defer rootSpan.End()
+
+	return s.repo.GetByID(ctx, userID, shortID)
+}
+
+func (s *LinksService) Update(ctx context.Context, userID string, shortID string, req *dto.UpdateReq) (*model.URL, error) {
+	if err := s.validator.ValidateStructCtx(ctx, req); err != nil {
+		return nil, err
+	}
+
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksService.Update", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	url, err := s.repo.GetByID(ctx, userID, shortID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.LongURL != nil {
+		url.LongURL, _ = validation.NormalizeLongURL(*req.LongURL) // already validated
+	}
+	if req.RedirectType != nil {
+		url.RedirectType = *req.RedirectType
+	}
+	if req.ExpiresAt != nil {
+		plan, err := s.plans.GetPlan(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if maxExpiry := plan.MaxExpiry(url.CreatedAt); !maxExpiry.IsZero() && req.ExpiresAt.After(maxExpiry) {
+			return nil, errors.New(response.ExpiryNotInPlan)
+		}
+		url.ExpiresAt = *req.ExpiresAt
+	}
+
+	if err = s.repo.Update(ctx, url); err != nil {
+		logger.Infof("Update.Update fail, short_id: %s, error: %s", shortID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, err
+	}
+
+	s.evict(ctx, shortID)
+	return url, nil
+}
+
+func (s *LinksService) Delete(ctx context.Context, userID string, shortID string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksService.Delete", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	if err := s.repo.Delete(ctx, userID, shortID); err != nil {
+		if err.Error() != response.IDNotFound {
+			logger.Infof("Delete.Delete fail, short_id: %s, error: %s", shortID, err)
+			logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		}
+		return err
+	}
+
+	s.evict(ctx, shortID)
+	return nil
+}
+
+// evict removes the cached redirect written by the retrieve handler so that
+// the change is visible on the next visit
+func (s *LinksService) evict(ctx context.Context, shortID string) {
+	if err := s.redis.Delete(shortID); err != nil {
+		traceContextFields := apmzap.TraceContext(ctx)
+		logger.Infof("failed to evict cache, short_id: %s, error: %s", shortID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	}
+}
+
+func encodeCursor(c *repository.Cursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ShortID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (*repository.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	nanos, shortID, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, errors.New(response.InvalidCursor)
+	}
+
+	ns, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Cursor{CreatedAt: time.Unix(0, ns), ShortID: shortID}, nil
+}
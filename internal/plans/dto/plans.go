@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+)
+
+type Plan struct {
+	Name          string `json:"name"`
+	DailyQuota    int    `json:"daily_quota"`
+	MonthlyQuota  int    `json:"monthly_quota"`
+	MaxExpiryDays int    `json:"max_expiry_days"`
+	CustomAliases bool   `json:"custom_aliases"`
+	PasswordLinks bool   `json:"password_links"`
+}
+
+type Quota struct {
+	Used     int       `json:"used"`
+	Limit    int       `json:"limit"`
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+type UsageRes struct {
+	Plan    Plan  `json:"plan"`
+	Daily   Quota `json:"daily"`
+	Monthly Quota `json:"monthly"`
+}
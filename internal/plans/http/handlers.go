@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/plans/dto"
+	"shortbin/internal/plans/service"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+	"shortbin/pkg/utils"
+)
+
+type PlansHandler struct {
+	service service.IPlansService
+}
+
+func NewPlansHandler(service service.IPlansService) *PlansHandler {
+	return &PlansHandler{
+		service: service,
+	}
+}
+
+// GetUsage godoc
+//
+//	@Summary	My plan and link creation quota usage
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.UsageRes
+//	@Router		/api/v1/me/usage [get]
+func (h *PlansHandler) GetUsage(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	usage, err := h.service.GetUsage(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == response.PlanNotFound {
+			response.Error(c, http.StatusNotFound, err, response.UserNotFound)
+			return
+		}
+
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	var res dto.UsageRes
+	utils.Copy(&res, &usage)
+	response.JSON(c, http.StatusOK, res)
+}
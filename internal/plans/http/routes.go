@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/plans/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/middleware"
+)
+
+func Routes(r *gin.RouterGroup, store storage.Store, apiKeys middleware.APIKeyValidator) {
+	plansRepo := store.Plans()
+	plansSvc := service.NewPlansService(plansRepo)
+	plansHandler := NewPlansHandler(plansSvc)
+
+	authMiddleware := middleware.JWTAuth(apiKeys)
+	r.GET("/me/usage", authMiddleware, middleware.RequireScope(middleware.ScopeStats), plansHandler.GetUsage)
+}
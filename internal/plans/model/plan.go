@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	// DefaultPlan is the plan of new users
+	DefaultPlan = "free"
+	// AnonymousPlan applies to links created without logging in
+	AnonymousPlan = "anonymous"
+)
+
+// Plan tier of a user, zero quotas and max expiry mean unlimited
+type Plan struct {
+	Name          string `json:"name"`
+	DailyQuota    int    `json:"daily_quota"`
+	MonthlyQuota  int    `json:"monthly_quota"`
+	MaxExpiryDays int    `json:"max_expiry_days"`
+	CustomAliases bool   `json:"custom_aliases"`
+	PasswordLinks bool   `json:"password_links"`
+}
+
+// MaxExpiry of a link created at createdAt, zero when unlimited
+func (p *Plan) MaxExpiry(createdAt time.Time) time.Time {
+	if p.MaxExpiryDays <= 0 {
+		return time.Time{}
+	}
+	return createdAt.AddDate(0, 0, p.MaxExpiryDays)
+}
+
+// Quota usage of one period, a zero Limit is unlimited
+type Quota struct {
+	Used     int       `json:"used"`
+	Limit    int       `json:"limit"`
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+// Usage of a user's link creation quotas
+type Usage struct {
+	Plan    *Plan `json:"plan"`
+	Daily   Quota `json:"daily"`
+	Monthly Quota `json:"monthly"`
+}
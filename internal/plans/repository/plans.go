@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/plans/model"
+	"shortbin/pkg/response"
+)
+
+type IPlansRepository interface {
+	GetPlanByName(ctx context.Context, name string) (*model.Plan, error)
+	GetPlanByUserID(ctx context.Context, userID string) (*model.Plan, error)
+	GetUsage(ctx context.Context, userID string, day time.Time, monthStart time.Time) (int, int, error)
+	Reserve(ctx context.Context, userID string, day time.Time, dailyQuota int, count int) (bool, error)
+	Release(ctx context.Context, userID string, day time.Time, count int) error
+}
+
+type PlansRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPlansRepository(db *pgxpool.Pool) *PlansRepo {
+	return &PlansRepo{db: db}
+}
+
+const planColumns = `p.name, p.daily_quota, p.monthly_quota, p.max_expiry_days, p.custom_aliases, p.password_links`
+
+func (r *PlansRepo) GetPlanByName(ctx context.Context, name string) (*model.Plan, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansRepo.GetPlanByName", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT ` + planColumns + ` FROM plans p WHERE p.name=$1`
+
+	return scanPlan(r.db.QueryRow(ctx, query, name))
+}
+
+func (r *PlansRepo) GetPlanByUserID(ctx context.Context, userID string) (*model.Plan, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansRepo.GetPlanByUserID", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT ` + planColumns + ` FROM users u JOIN plans p ON p.name = u.plan WHERE u.id=$1`
+
+	return scanPlan(r.db.QueryRow(ctx, query, userID))
+}
+
+// GetUsage returns the number of links created by the user on day and since
+// monthStart
+func (r *PlansRepo) GetUsage(ctx context.Context, userID string, day time.Time, monthStart time.Time) (int, int, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansRepo.GetUsage", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT COALESCE(SUM(created) FILTER (WHERE day = $2), 0), COALESCE(SUM(created), 0)
+		FROM link_usage_daily WHERE user_id=$1 AND day >= $3`
+
+	var daily, monthly int
+	if err := r.db.QueryRow(ctx, query, userID, day, monthStart).Scan(&daily, &monthly); err != nil {
+		return 0, 0, err
+	}
+
+	return daily, monthly, nil
+}
+
+// Reserve counts count more links created by the user on day, unless that
+// would exceed dailyQuota. A zero dailyQuota is unlimited.
+func (r *PlansRepo) Reserve(ctx context.Context, userID string, day time.Time, dailyQuota int, count int) (bool, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansRepo.Reserve", "repository")
+	defer rootSpan.End()
+
+	// the conditional upsert makes check and increment one atomic step, so
+	// concurrent creates can not overshoot the daily quota
+	query := `INSERT INTO link_usage_daily (user_id, day, created) SELECT $1, $2, $4 WHERE $3 = 0 OR $4 <= $3
+		ON CONFLICT (user_id, day) DO UPDATE SET created = link_usage_daily.created + $4
+		WHERE $3 = 0 OR link_usage_daily.created + $4 <= $3
+		RETURNING created`
+
+	var created int
+	if err := r.db.QueryRow(ctx, query, userID, day, dailyQuota, count).Scan(&created); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Release gives back reservations for links that were not created after all
+func (r *PlansRepo) Release(ctx context.Context, userID string, day time.Time, count int) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansRepo.Release", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE link_usage_daily SET created = GREATEST(created - $3, 0) WHERE user_id=$1 AND day=$2`
+	_, err := r.db.Exec(ctx, query, userID, day, count)
+	return err
+}
+
+func scanPlan(row pgx.Row) (*model.Plan, error) {
+	var plan model.Plan
+	if err := row.Scan(&plan.Name, &plan.DailyQuota, &plan.MonthlyQuota, &plan.MaxExpiryDays, &plan.CustomAliases, &plan.PasswordLinks); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(response.PlanNotFound)
+		}
+		return nil, err
+	}
+
+	return &plan, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.elastic.co/apm/module/apmzap/v2"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/plans/model"
+	"shortbin/internal/plans/repository"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+)
+
+//go:generate mockery --name=IPlansService
+type IPlansService interface {
+	GetPlan(ctx context.Context, userID string) (*model.Plan, error)
+	GetUsage(ctx context.Context, userID string) (*model.Usage, error)
+	Reserve(ctx context.Context, userID string, plan *model.Plan, count int) error
+	Release(ctx context.Context, userID string, count int)
+}
+
+type PlansService struct {
+	repo repository.IPlansRepository
+}
+
+func NewPlansService(
+	repo repository.IPlansRepository) *PlansService {
+	return &PlansService{
+		repo: repo,
+	}
+}
+
+// GetPlan of the user, links created without logging in get the anonymous plan
+func (s *PlansService) GetPlan(ctx context.Context, userID string) (*model.Plan, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansService.GetPlan", "service")
+	defer rootSpan.End()
+
+	if userID == "" {
+		return s.repo.GetPlanByName(ctx, model.AnonymousPlan)
+	}
+	return s.repo.GetPlanByUserID(ctx, userID)
+}
+
+func (s *PlansService) GetUsage(ctx context.Context, userID string) (*model.Usage, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansService.GetUsage", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	plan, err := s.repo.GetPlanByUserID(ctx, userID)
+	if err != nil {
+		logger.Infof("GetUsage.GetPlanByUserID fail, userID: %s, error: %s", userID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, err
+	}
+
+	day, monthStart := periods(time.Now())
+	daily, monthly, err := s.repo.GetUsage(ctx, userID, day, monthStart)
+	if err != nil {
+		logger.Infof("GetUsage.GetUsage fail, userID: %s, error: %s", userID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		return nil, err
+	}
+
+	return &model.Usage{
+		Plan:    plan,
+		Daily:   model.Quota{Used: daily, Limit: plan.DailyQuota, ResetsAt: day.AddDate(0, 0, 1)},
+		Monthly: model.Quota{Used: monthly, Limit: plan.MonthlyQuota, ResetsAt: monthStart.AddDate(0, 1, 0)},
+	}, nil
+}
+
+// Reserve counts count links against the user's quotas, failing with
+// QuotaExceeded when either would be exceeded. The daily quota is enforced
+// atomically, the monthly one can be overshot by concurrent creates at the
+// very end of the month's quota.
+func (s *PlansService) Reserve(ctx context.Context, userID string, plan *model.Plan, count int) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*PlansService.Reserve", "service")
+	defer rootSpan.End()
+
+	day, monthStart := periods(time.Now())
+
+	if plan.MonthlyQuota > 0 {
+		_, monthly, err := s.repo.GetUsage(ctx, userID, day, monthStart)
+		if err != nil {
+			return err
+		}
+		if monthly+count > plan.MonthlyQuota {
+			return errors.New(response.QuotaExceeded)
+		}
+	}
+
+	reserved, err := s.repo.Reserve(ctx, userID, day, plan.DailyQuota, count)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return errors.New(response.QuotaExceeded)
+	}
+
+	return nil
+}
+
+// Release gives back count reservations after links could not be created
+func (s *PlansService) Release(ctx context.Context, userID string, count int) {
+	day, _ := periods(time.Now())
+	if err := s.repo.Release(ctx, userID, day, count); err != nil {
+		traceContextFields := apmzap.TraceContext(ctx)
+		logger.Infof("failed to release quota, userID: %s, error: %s", userID, err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	}
+}
+
+// periods returns the start of the day and of the month of now, in UTC
+func periods(now time.Time) (time.Time, time.Time) {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
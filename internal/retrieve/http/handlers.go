@@ -1,33 +1,52 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"math"
+	"math/rand"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.elastic.co/apm/module/apmzap/v2"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
+	"shortbin/internal/common/model"
 	"shortbin/internal/retrieve/service"
 	"shortbin/pkg/config"
+	"shortbin/pkg/events"
+	"shortbin/pkg/jwt"
 	"shortbin/pkg/kafka"
 	"shortbin/pkg/logger"
 	"shortbin/pkg/redis"
 	"shortbin/pkg/response"
 )
 
+const (
+	defaultNegativeTTL = time.Minute
+	// fillTimeout bounds a shared fill, detached from the requests waiting
+	// on it
+	fillTimeout = 5 * time.Second
+)
+
 type RetrieveHandler struct {
-	service       service.IRetrieveService
-	kafkaProducer kafka.IKafkaProducer
-	redis         redis.IRedis
+	service service.IRetrieveService
+	clicks  kafka.Publisher[*events.ClickEvent]
+	redis   redis.IRedis
+	// fills coalesces the database lookup and cache write of concurrent
+	// misses of the same short ID
+	fills singleflight.Group
 }
 
-func NewRetrieveHandler(service service.IRetrieveService, kafkaProducer kafka.IKafkaProducer, redis redis.IRedis) *RetrieveHandler {
+func NewRetrieveHandler(service service.IRetrieveService, clicks kafka.Publisher[*events.ClickEvent], redis redis.IRedis) *RetrieveHandler {
 	return &RetrieveHandler{
-		service:       service,
-		kafkaProducer: kafkaProducer,
-		redis:         redis,
+		service: service,
+		clicks:  clicks,
+		redis:   redis,
 	}
 }
 
@@ -37,62 +56,179 @@ func NewRetrieveHandler(service service.IRetrieveService, kafkaProducer kafka.IK
 // @Tags urls
 // @Produce json
 // @Param short_id path string true "Short ID"
-// @Success 301 {string} string "Redirects to the long URL"
+// @Success 301 {string} string "Redirects to the long URL, 302, 307 or 308 depending on the link's redirect_type"
 // @Failure 404 {object} response.ErrorResponse "id not Found"
+// @Failure 410 {object} response.ErrorResponse "link expired"
 // @Router /{short_id} [get]
 func (h *RetrieveHandler) Retrieve(c *gin.Context) {
 	shortID := c.Param("short_id")
 	traceContextFields := apmzap.TraceContext(c.Request.Context())
 
-	var value string // inits to ""
-	if err := h.redis.GetByRefreshingExpiry(shortID, &value); !errors.Is(err, redis.NilReturn) && err != nil {
+	var entry model.CachedURL
+	if err := h.redis.GetByRefreshingExpiry(shortID, &entry); err != nil && !isCacheMiss(err) {
 		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
 	}
 
-	var longURL string
-	var userID string
-	if value == "" {
-		var err error
-		var subUserID *string
-		longURL, subUserID, err = h.service.Retrieve(c, shortID)
-		if err != nil {
-			if e := err.Error(); e == response.IDNotFound || e == response.IDLengthNotInRange {
+	cached := entry.Version == model.CachedURLVersion
+	if !cached || shouldRefresh(&entry) {
+		refreshed, err := h.fill(c.Request.Context(), traceContextFields, shortID)
+		switch {
+		case err == nil:
+			entry = refreshed
+		case cached && err.Error() != response.IDNotFound && err.Error() != response.LinkExpired:
+			// an early refresh failing is no reason to fail the redirect
+			logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		default:
+			switch e := err.Error(); e {
+			case response.IDNotFound, response.IDLengthNotInRange:
 				response.Error(c, http.StatusNotFound, err, response.IDNotFound)
-			} else {
+			case response.LinkExpired:
+				response.Error(c, http.StatusGone, err, response.LinkExpired)
+			default:
 				logger.ApmLogger.With(traceContextFields...).Error(err.Error())
 				response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
 			}
 			return
 		}
-		if userID = "-1"; subUserID != nil {
-			userID = *subUserID
+	}
+
+	if entry.NotFound {
+		response.Error(c, http.StatusNotFound, errors.New(response.IDNotFound), response.IDNotFound)
+		return
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		go evict(h, traceContextFields, shortID)
+		response.Error(c, http.StatusGone, errors.New(response.LinkExpired), response.LinkExpired)
+		return
+	}
+
+	if entry.Protected && !isUnlocked(c, shortID) {
+		renderPasswordPrompt(c, http.StatusOK, "")
+		return
+	}
+
+	produce(h, c, shortID, entry.UserID, entry.LongURL)
+	if entry.Protected {
+		// a cached redirect would skip the password prompt once the
+		// unlock cookie expires
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(protectedRedirectStatus(entry.RedirectType), entry.LongURL)
+		return
+	}
+	c.Redirect(redirectStatus(entry.RedirectType), entry.LongURL)
+}
+
+// Unlock godoc
+//
+// @Summary Unlock a password protected short URL
+// @Tags urls
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param short_id path string true "Short ID"
+// @Param password formData string true "Link password"
+// @Success 303 {string} string "Sets the unlock cookie and redirects back to the short URL"
+// @Failure 401 {string} string "wrong password"
+// @Failure 429 {string} string "too many attempts"
+// @Router /{short_id} [post]
+func (h *RetrieveHandler) Unlock(c *gin.Context) {
+	shortID := c.Param("short_id")
+	traceContextFields := apmzap.TraceContext(c.Request.Context())
+
+	cfg := config.GetConfig().LinkPassword
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxUnlockAttempts
+	}
+	window := cfg.AttemptWindow * time.Minute
+	if window <= 0 {
+		window = defaultUnlockAttemptWindow
+	}
+
+	// every attempt is counted before the password is checked, so that
+	// concurrent guesses cannot all pass the limit
+	attemptsKey := unlockAttemptsKeyPrefix + shortID
+	attempts, err := h.redis.Incr(attemptsKey, window)
+	if err != nil {
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	}
+	if attempts > int64(maxAttempts) {
+		c.Header("Retry-After", strconv.Itoa(int(window.Seconds())))
+		renderPasswordPrompt(c, http.StatusTooManyRequests, response.TooManyAttempts)
+		return
+	}
+
+	err = h.service.Unlock(c.Request.Context(), shortID, c.PostForm("password"))
+	if err != nil {
+		switch e := err.Error(); e {
+		case response.WrongPassword:
+			renderPasswordPrompt(c, http.StatusUnauthorized, response.WrongPassword)
+		case response.IDNotFound, response.IDLengthNotInRange:
+			response.Error(c, http.StatusNotFound, err, response.IDNotFound)
+		case response.LinkExpired:
+			response.Error(c, http.StatusGone, err, response.LinkExpired)
+		default:
+			logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+			response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
 		}
-		go cache(h, c, shortID, userID, longURL)
-	} else {
-		split := strings.SplitN(value, ";", 2)
-		userID, longURL = split[0], split[1]
+		return
 	}
-	go produce(h, c, shortID, userID, longURL)
-	c.Redirect(http.StatusMovedPermanently, longURL)
+
+	if err = h.redis.Delete(attemptsKey); err != nil {
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	}
+
+	token := jwt.GenerateAccessToken(map[string]interface{}{"short_id": shortID}, jwt.LinkUnlockTokenType)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(unlockCookieName, token, jwt.LinkUnlockExpiryTime, "/"+shortID, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusSeeOther, "/"+shortID)
 }
 
-func produce(h *RetrieveHandler, c *gin.Context, shortID string, shortCreatedBy string, longURL string) {
-	value := map[string]string{
-		"short_id":         shortID,
-		"short_created_by": shortCreatedBy,
-		"long_url":         longURL,
-		"ip_address":       c.ClientIP(),
-		"user_agent":       c.GetHeader("User-Agent"),
-		"referer":          c.GetHeader("Referer"),
-		"x_forwarded_for":  c.GetHeader("X-Forwarded-For"),
-		"request_host":     c.Request.Host,
+// redirectStatus returns the stored redirect type of a link, falling back to
+// the configured default for links created before redirect types existed
+func redirectStatus(redirectType int) int {
+	switch redirectType {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return redirectType
+	}
+
+	switch redirectType = config.GetConfig().RedirectType; redirectType {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return redirectType
+	default:
+		return http.StatusMovedPermanently
+	}
+}
+
+// protectedRedirectStatus is the non-permanent counterpart of a link's
+// redirect type, browsers keep permanent redirects regardless of caching
+func protectedRedirectStatus(redirectType int) int {
+	switch redirectStatus(redirectType) {
+	case http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return http.StatusTemporaryRedirect
+	default:
+		return http.StatusFound
 	}
+}
+
+// produce enqueues the click event, the producer writes it to Kafka in the
+// background so this never waits on the broker
+func produce(h *RetrieveHandler, c *gin.Context, shortID string, shortCreatedBy string, longURL string) {
+	event := events.NewClickEvent()
+	event.ShortID = shortID
+	event.ShortCreatedBy = shortCreatedBy
+	event.LongURL = longURL
+	event.IPAddress = c.ClientIP()
+	event.UserAgent = c.GetHeader("User-Agent")
+	event.Referer = c.GetHeader("Referer")
+	event.XForwardedFor = c.GetHeader("X-Forwarded-For")
+	event.RequestHost = c.Request.Host
+	event.Country = c.GetHeader(countryHeader())
 
 	var err error
 	if shortCreatedBy == "-1" {
-		err = h.kafkaProducer.Produce(c, config.GetConfig().Kafka.PublicClicksTopic, shortID, value)
+		err = h.clicks.Publish(c.Request.Context(), config.GetConfig().Kafka.PublicClicksTopic, shortID, event)
 	} else {
-		err = h.kafkaProducer.Produce(c, config.GetConfig().Kafka.ClicksTopic, shortID, value)
+		err = h.clicks.Publish(c.Request.Context(), config.GetConfig().Kafka.ClicksTopic, shortID, event)
 	}
 	traceContextFields := apmzap.TraceContext(c.Request.Context())
 	if err != nil {
@@ -101,10 +237,106 @@ func produce(h *RetrieveHandler, c *gin.Context, shortID string, shortCreatedBy
 	}
 }
 
-func cache(h *RetrieveHandler, c *gin.Context, shortID string, userID string, longURL string) {
-	traceContextFields := apmzap.TraceContext(c.Request.Context())
-	if err := h.redis.Set(shortID, userID+";"+longURL, config.GetConfig().Redis.TTL*time.Minute); err != nil {
+// countryHeader is the header carrying the visitor's ISO country code, as set
+// by the CDN or load balancer in front of shortbin
+func countryHeader() string {
+	if header := config.GetConfig().CountryHeader; header != "" {
+		return header
+	}
+	return "CF-IPCountry"
+}
+
+// fill reads shortID through the service and caches the result. Concurrent
+// callers for the same short ID wait for and share a single lookup and cache
+// write, so an expired or evicted hot link does not stampede the database.
+// The shared fill outlives the request of the first caller, which may go
+// away while others wait on it.
+func (h *RetrieveHandler) fill(ctx context.Context, traceContextFields []zap.Field, shortID string) (model.CachedURL, error) {
+	v, err, _ := h.fills.Do(shortID, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fillTimeout)
+		defer cancel()
+
+		start := time.Now()
+		url, err := h.service.Retrieve(ctx, shortID)
+		if err != nil {
+			if err.Error() == response.IDNotFound {
+				cacheNotFound(h, traceContextFields, shortID)
+			}
+			return nil, err
+		}
+
+		entry := model.NewCachedURL(url)
+		entry.Delta = time.Since(start)
+		cache(h, traceContextFields, shortID, entry)
+		return *entry, nil
+	})
+	if err != nil {
+		return model.CachedURL{}, err
+	}
+
+	return v.(model.CachedURL), nil
+}
+
+// shouldRefresh decides whether this request re-reads the entry ahead of its
+// RefreshAt, with a probability growing as it nears, so that a single request
+// refreshes a hot link instead of all of them at once (XFetch)
+func shouldRefresh(entry *model.CachedURL) bool {
+	cfg := config.GetConfig().Redis.EarlyRefresh
+	if !cfg.Enabled || entry.NotFound || entry.RefreshAt.IsZero() {
+		return false
+	}
+
+	beta := cfg.Beta
+	if beta <= 0 {
+		beta = 1
+	}
+
+	gap := time.Duration(float64(entry.Delta) * beta * -math.Log(1-rand.Float64()))
+	return !time.Now().Add(gap).Before(entry.RefreshAt)
+}
+
+func cache(h *RetrieveHandler, traceContextFields []zap.Field, shortID string, entry *model.CachedURL) {
+	// never keep a link cached beyond its own expiry
+	ttl := config.GetConfig().Redis.TTL * time.Minute
+	if remaining := time.Until(entry.ExpiresAt); remaining < ttl {
+		ttl = remaining
+	}
+	if ttl <= 0 {
+		return
+	}
+	entry.RefreshAt = time.Now().Add(ttl)
+
+	if err := h.redis.Set(shortID, entry, ttl); err != nil {
 		logger.Infof("failed to set cache: %v", err)
 		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
 	}
 }
+
+// cacheNotFound caches the absence of shortID for a short while, so repeated
+// lookups of unknown IDs stay off the database. Creating the ID evicts it.
+func cacheNotFound(h *RetrieveHandler, traceContextFields []zap.Field, shortID string) {
+	ttl := config.GetConfig().Redis.NegativeTTL * time.Second
+	if ttl <= 0 {
+		ttl = defaultNegativeTTL
+	}
+
+	entry := model.CachedURL{Version: model.CachedURLVersion, NotFound: true}
+	if err := h.redis.Set(shortID, entry, ttl); err != nil {
+		logger.Infof("failed to set cache: %v", err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	}
+}
+
+// isCacheMiss reports whether err means there is no usable cache entry,
+// including entries written in an older format
+func isCacheMiss(err error) bool {
+	var typeErr *json.UnmarshalTypeError
+	return errors.Is(err, redis.NilReturn) || errors.As(err, &typeErr)
+}
+
+func evict(h *RetrieveHandler, traceContextFields []zap.Field, shortID string) {
+	if err := h.redis.Delete(shortID); err != nil {
+		logger.Infof("failed to evict cache: %v", err)
+		logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	}
+}
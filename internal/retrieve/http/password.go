@@ -0,0 +1,58 @@
+package http
+
+import (
+	"html/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/pkg/jwt"
+)
+
+const (
+	unlockCookieName           = "shortbin_unlock"
+	unlockAttemptsKeyPrefix    = "unlock_attempts:"
+	defaultMaxUnlockAttempts   = 5
+	defaultUnlockAttemptWindow = 15 * time.Minute
+)
+
+var passwordPrompt = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+</head>
+<body>
+<form method="post">
+<p>This link is password protected.</p>
+{{if .}}<p role="alert">{{.}}</p>{{end}}
+<input type="password" name="password" autocomplete="current-password" autofocus required>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+func renderPasswordPrompt(c *gin.Context, status int, message string) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	_ = passwordPrompt.Execute(c.Writer, message)
+}
+
+// isUnlocked reports whether the request carries a valid unlock cookie for shortID
+func isUnlocked(c *gin.Context, shortID string) bool {
+	token, err := c.Cookie(unlockCookieName)
+	if err != nil || token == "" {
+		return false
+	}
+
+	payload, err := jwt.ValidateToken(token)
+	if err != nil || payload == nil || payload["type"] != jwt.LinkUnlockTokenType {
+		return false
+	}
+
+	return payload["short_id"] == shortID
+}
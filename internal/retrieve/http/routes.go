@@ -2,18 +2,22 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 
-	"shortbin/internal/retrieve/repository"
 	"shortbin/internal/retrieve/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/bloom"
+	"shortbin/pkg/events"
 	"shortbin/pkg/kafka"
+	"shortbin/pkg/ratelimit"
 	"shortbin/pkg/redis"
 )
 
-func Routes(e *gin.Engine, dbPool *pgxpool.Pool, kafkaProducer kafka.IKafkaProducer, cache redis.IRedis) {
-	retrieveRepo := repository.NewRetrieveRepository(dbPool)
-	retrieveSvc := service.NewRetrieveService(retrieveRepo)
-	retrieveHandler := NewRetrieveHandler(retrieveSvc, kafkaProducer, cache)
+func Routes(e *gin.Engine, store storage.Store, clicks kafka.Publisher[*events.ClickEvent], cache redis.IRedis, filter bloom.Filter, limiter *ratelimit.Limiter) {
+	retrieveRepo := store.Retrieve()
+	retrieveSvc := service.NewRetrieveService(retrieveRepo, filter)
+	retrieveHandler := NewRetrieveHandler(retrieveSvc, clicks, cache)
 
-	e.GET("/:short_id", retrieveHandler.Retrieve)
+	rateLimitMiddleware := ratelimit.Middleware(limiter, "redirect")
+	e.GET("/:short_id", rateLimitMiddleware, retrieveHandler.Retrieve)
+	e.POST("/:short_id", rateLimitMiddleware, retrieveHandler.Unlock)
 }
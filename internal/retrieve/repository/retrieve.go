@@ -1,9 +1,9 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
-	"github.com/gin-gonic/gin"
 	"go.elastic.co/apm/v2"
 
 	"github.com/jackc/pgx/v5"
@@ -13,7 +13,8 @@ import (
 )
 
 type IRetrieveRepository interface {
-	GetURLByID(ctx *gin.Context, id string) (*model.URL, error)
+	GetURLByID(ctx context.Context, id string) (*model.URL, error)
+	ListShortIDs(ctx context.Context, after string, limit int) ([]string, error)
 }
 
 type RetrieveRepo struct {
@@ -24,17 +25,16 @@ func NewRetrieveRepository(db *pgxpool.Pool) *RetrieveRepo {
 	return &RetrieveRepo{db: db}
 }
 
-func (r *RetrieveRepo) GetURLByID(ctx *gin.Context, id string) (*model.URL, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*RetrieveRepo.GetURLByID", "repository", nil)
+func (r *RetrieveRepo) GetURLByID(ctx context.Context, id string) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*RetrieveRepo.GetURLByID", "repository")
 	defer rootSpan.End()
 
-	query := `SELECT short_id, long_url, user_id, created_at, expires_at FROM urls WHERE short_id=$1`
+	query := `SELECT short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at FROM urls WHERE short_id=$1`
 
 	row := r.db.QueryRow(ctx, query, id)
 
 	var url model.URL
-	if err := row.Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.CreatedAt, &url.ExpiresAt); err != nil {
+	if err := row.Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.RedirectType, &url.HashedPassword, &url.CreatedAt, &url.ExpiresAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, errors.New("id not found")
 		}
@@ -43,3 +43,16 @@ func (r *RetrieveRepo) GetURLByID(ctx *gin.Context, id string) (*model.URL, erro
 
 	return &url, nil
 }
+
+// ListShortIDs returns up to limit short IDs ordered after the given one, for
+// seeding the Bloom filter
+func (r *RetrieveRepo) ListShortIDs(ctx context.Context, after string, limit int) ([]string, error) {
+	query := `SELECT short_id FROM urls WHERE short_id > $1 ORDER BY short_id LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
@@ -1,47 +1,89 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"go.elastic.co/apm/v2"
+	"golang.org/x/crypto/bcrypt"
 
+	"shortbin/internal/common/model"
 	"shortbin/internal/retrieve/repository"
+	"shortbin/pkg/bloom"
 	"shortbin/pkg/config"
+	"shortbin/pkg/logger"
 	"shortbin/pkg/response"
 )
 
 //go:generate mockery --name=IRetrieveService
 type IRetrieveService interface {
-	Retrieve(ctx *gin.Context, shortID string) (string, *string, error)
+	Retrieve(ctx context.Context, shortID string) (*model.URL, error)
+	Unlock(ctx context.Context, shortID string, password string) error
 }
 
 type RetrieveService struct {
-	repo repository.IRetrieveRepository
+	repo   repository.IRetrieveRepository
+	filter bloom.Filter
 }
 
 func NewRetrieveService(
-	repo repository.IRetrieveRepository) *RetrieveService {
+	repo repository.IRetrieveRepository,
+	filter bloom.Filter) *RetrieveService {
 	return &RetrieveService{
-		repo: repo,
+		repo:   repo,
+		filter: filter,
 	}
 }
 
-func (s *RetrieveService) Retrieve(ctx *gin.Context, shortID string) (string, *string, error) {
-	apmTx := apm.TransactionFromContext(ctx.Request.Context())
-	rootSpan := apmTx.StartSpan("*RetrieveService.Retrieve", "service", nil)
+func (s *RetrieveService) Retrieve(ctx context.Context, shortID string) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*RetrieveService.Retrieve", "service")
 	defer rootSpan.End()
 
 	cfg := config.GetConfig()
 
 	if length := len(shortID); length < cfg.ShortIDLength.Min || cfg.ShortIDLength.Max < length {
-		return "", nil, errors.New(response.IDLengthNotInRange)
+		return nil, errors.New(response.IDLengthNotInRange)
+	}
+
+	// a failing filter must not take redirects down, so on error the
+	// lookup falls through to the database
+	exists, err := s.filter.MightContain(shortID)
+	if err != nil {
+		logger.Error("bloom filter lookup failed: ", err)
+	}
+	if !exists {
+		return nil, errors.New(response.IDNotFound)
 	}
 
 	url, err := s.repo.GetURLByID(ctx, shortID)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+
+	if time.Now().After(url.ExpiresAt) {
+		return nil, errors.New(response.LinkExpired)
+	}
+
+	return url, nil
+}
+
+func (s *RetrieveService) Unlock(ctx context.Context, shortID string, password string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*RetrieveService.Unlock", "service")
+	defer rootSpan.End()
+
+	url, err := s.Retrieve(ctx, shortID)
+	if err != nil {
+		return err
+	}
+
+	if url.HashedPassword == nil {
+		return nil
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(*url.HashedPassword), []byte(password)); err != nil {
+		return errors.New(response.WrongPassword)
 	}
 
-	return url.LongURL, url.UserID, nil
+	return nil
 }
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"shortbin/pkg/jwt"
+	"shortbin/pkg/response"
+)
+
+type userIDKey struct{}
+
+// authInterceptor authenticates every call with the access token in the
+// "authorization" metadata, the same token the HTTP API takes
+func authInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get("authorization")
+	if len(tokens) == 0 || tokens[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, response.Unauthorized)
+	}
+
+	payload, err := jwt.ValidateToken(tokens[0])
+	if err != nil || payload == nil || payload["type"] != jwt.LoginTokenType {
+		return nil, status.Error(codes.Unauthenticated, response.Unauthorized)
+	}
+
+	userID, _ := payload["id"].(string)
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, response.EmptyUserID)
+	}
+
+	return handler(context.WithValue(ctx, userIDKey{}, userID), req)
+}
+
+// authority is the host the client called, the gRPC counterpart of the HTTP
+// Host header that links to the service itself are checked against
+func authority(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if hosts := md.Get(":authority"); len(hosts) > 0 {
+		return hosts[0]
+	}
+	return ""
+}
+
+// userID of the caller, set by authInterceptor
+func userID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey{}).(string)
+	return id
+}
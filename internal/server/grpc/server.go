@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"go.elastic.co/apm/module/apmgrpc/v2"
+	"google.golang.org/grpc"
+
+	createService "shortbin/internal/create/service"
+	linksService "shortbin/internal/links/service"
+	plansService "shortbin/internal/plans/service"
+	retrieveService "shortbin/internal/retrieve/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/bloom"
+	"shortbin/pkg/config"
+	"shortbin/pkg/idgen"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/redis"
+	"shortbin/pkg/shortbinpb"
+	"shortbin/pkg/validation"
+)
+
+type Server struct {
+	cfg       *config.Config
+	validator validation.Validation
+	store     storage.Store
+	cache     redis.IRedis
+	idGen     idgen.IDGenerator
+	filter    bloom.Filter
+}
+
+func NewServer(
+	validator validation.Validation,
+	store storage.Store,
+	cache redis.IRedis,
+	idGen idgen.IDGenerator,
+	filter bloom.Filter,
+) *Server {
+	return &Server{
+		cfg:       config.GetConfig(),
+		validator: validator,
+		store:     store,
+		cache:     cache,
+		idGen:     idGen,
+		filter:    filter,
+	}
+}
+
+// Run serves gRPC until ctx is done or the listener fails, then stops
+// gracefully, waiting for in-flight calls. The store, cache and producer are
+// shared with the HTTP server, which closes them.
+func (s Server) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.cfg.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("listening for gRPC: %w", err)
+	}
+
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		apmgrpc.NewUnaryServerInterceptor(apmgrpc.WithRecovery()),
+		authInterceptor,
+	))
+	shortbinpb.RegisterShortbinServiceServer(srv, s.newService())
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("gRPC server is listening on port ", s.cfg.GRPCPort)
+		serveErr <- srv.Serve(lis)
+		close(serveErr)
+	}()
+
+	select {
+	case err = <-serveErr:
+		return fmt.Errorf("running gRPC server: %w", err)
+	case <-ctx.Done():
+		logger.Info("Shutting down gRPC server")
+	}
+
+	srv.GracefulStop()
+	return nil
+}
+
+func (s Server) newService() *ShortbinService {
+	plansSvc := plansService.NewPlansService(s.store.Plans())
+	createSvc := createService.NewCreateService(s.validator, s.store.Create(), s.idGen, s.cache, s.filter, plansSvc)
+	retrieveSvc := retrieveService.NewRetrieveService(s.store.Retrieve(), s.filter)
+	linksSvc := linksService.NewLinksService(s.validator, s.store.Links(), s.cache, plansSvc)
+
+	return NewShortbinService(s.validator, createSvc, retrieveSvc, linksSvc)
+}
@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"shortbin/internal/common/model"
+	createDto "shortbin/internal/create/dto"
+	createService "shortbin/internal/create/service"
+	linksDto "shortbin/internal/links/dto"
+	linksService "shortbin/internal/links/service"
+	retrieveService "shortbin/internal/retrieve/service"
+	"shortbin/pkg/database"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+	"shortbin/pkg/shortbinpb"
+	"shortbin/pkg/validation"
+)
+
+// ShortbinService implements shortbinpb.ShortbinServiceServer on top of the
+// services behind the HTTP API
+type ShortbinService struct {
+	shortbinpb.UnimplementedShortbinServiceServer
+	validator validation.Validation
+	create    createService.ICreateService
+	retrieve  retrieveService.IRetrieveService
+	links     linksService.ILinksService
+}
+
+func NewShortbinService(
+	validator validation.Validation,
+	create createService.ICreateService,
+	retrieve retrieveService.IRetrieveService,
+	links linksService.ILinksService,
+) *ShortbinService {
+	return &ShortbinService{
+		validator: validator,
+		create:    create,
+		retrieve:  retrieve,
+		links:     links,
+	}
+}
+
+func (s *ShortbinService) Create(ctx context.Context, req *shortbinpb.CreateRequest) (*shortbinpb.Link, error) {
+	createReq := createDto.CreateReq{
+		LongURL:      req.GetLongUrl(),
+		CustomAlias:  req.GetCustomAlias(),
+		RedirectType: int(req.GetRedirectType()),
+		Password:     req.GetPassword(),
+		RequestHost:  authority(ctx),
+	}
+	if req.GetExpiresAt() != nil {
+		expiresAt := req.GetExpiresAt().AsTime()
+		createReq.ExpiresAt = &expiresAt
+	}
+
+	// validated here as well to tell invalid arguments from failures
+	if err := s.validator.ValidateStructCtx(ctx, &createReq); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	url, err := s.create.Create(ctx, userID(ctx), &createReq)
+	if err != nil {
+		if database.IsUniqueViolation(err) && createReq.CustomAlias != "" {
+			return nil, status.Error(codes.AlreadyExists, response.AliasAlreadyExists)
+		}
+		// the length of a custom alias, not an unknown short ID
+		if err.Error() == response.IDLengthNotInRange {
+			return nil, status.Error(codes.InvalidArgument, response.IDLengthNotInRange)
+		}
+		return nil, toStatus(err)
+	}
+
+	return toLink(url), nil
+}
+
+// Resolve returns the long URL of a short ID. Clicks are only recorded for
+// redirects, and the long URL of a password protected link is left out.
+func (s *ShortbinService) Resolve(ctx context.Context, req *shortbinpb.ResolveRequest) (*shortbinpb.ResolveResponse, error) {
+	url, err := s.retrieve.Retrieve(ctx, req.GetShortId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &shortbinpb.ResolveResponse{
+		RedirectType: int32(url.RedirectType),
+		Protected:    url.HashedPassword != nil,
+		ExpiresAt:    timestamppb.New(url.ExpiresAt),
+	}
+	if !res.Protected {
+		res.LongUrl = url.LongURL
+	}
+
+	return res, nil
+}
+
+func (s *ShortbinService) GetLink(ctx context.Context, req *shortbinpb.GetLinkRequest) (*shortbinpb.Link, error) {
+	url, err := s.links.Get(ctx, userID(ctx), req.GetShortId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toLink(url), nil
+}
+
+func (s *ShortbinService) ListLinks(ctx context.Context, req *shortbinpb.ListLinksRequest) (*shortbinpb.ListLinksResponse, error) {
+	listReq := linksDto.ListReq{
+		Cursor: req.GetCursor(),
+		Limit:  int(req.GetLimit()),
+	}
+	if err := s.validator.ValidateStruct(&listReq); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	urls, nextCursor, err := s.links.List(ctx, userID(ctx), &listReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &shortbinpb.ListLinksResponse{
+		Links:      make([]*shortbinpb.Link, 0, len(urls)),
+		NextCursor: nextCursor,
+	}
+	for _, url := range urls {
+		res.Links = append(res.Links, toLink(url))
+	}
+
+	return res, nil
+}
+
+func (s *ShortbinService) DeleteLink(ctx context.Context, req *shortbinpb.DeleteLinkRequest) (*shortbinpb.DeleteLinkResponse, error) {
+	if err := s.links.Delete(ctx, userID(ctx), req.GetShortId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &shortbinpb.DeleteLinkResponse{}, nil
+}
+
+func toLink(url *model.URL) *shortbinpb.Link {
+	return &shortbinpb.Link{
+		ShortId:      url.ShortID,
+		LongUrl:      url.LongURL,
+		RedirectType: int32(url.RedirectType),
+		CreatedAt:    timestamp(url.CreatedAt),
+		ExpiresAt:    timestamp(url.ExpiresAt),
+	}
+}
+
+// timestamp leaves zero times unset rather than sending the year 1
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// toStatus maps service errors to status codes the way the HTTP handlers map
+// them to status codes of their own
+func toStatus(err error) error {
+	if validation.IsInvalid(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	switch e := err.Error(); e {
+	case response.IDNotFound, response.IDLengthNotInRange:
+		return status.Error(codes.NotFound, response.IDNotFound)
+	case response.LinkExpired:
+		return status.Error(codes.NotFound, e)
+	case response.InvalidAlias, response.AliasReserved, response.ExpiryNotInPlan, response.InvalidCursor:
+		return status.Error(codes.InvalidArgument, e)
+	case response.FeatureNotInPlan:
+		return status.Error(codes.PermissionDenied, e)
+	case response.QuotaExceeded:
+		return status.Error(codes.ResourceExhausted, e)
+	}
+
+	logger.Error(err.Error())
+	return status.Error(codes.Internal, response.SomethingWentWrong)
+}
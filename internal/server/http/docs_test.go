@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/docs"
+	"shortbin/internal/storage/memory"
+	"shortbin/pkg/bloom"
+	"shortbin/pkg/idgen"
+	"shortbin/pkg/validation"
+)
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// TestDocsUpToDate fails when the committed spec was not regenerated after
+// an annotation changed. The generator is a module of its own, so it is run
+// with the go command, which builds it and may download its dependencies, so
+// the test is skipped with -short and left to a CI step of its own.
+func TestDocsUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the spec generator, skipped with -short")
+	}
+
+	committed, err := docs.FS.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := filepath.Abs("../../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "openapi.json")
+
+	cmd := exec.Command("go", "-C", filepath.Join(root, "tools", "openapi"), "run", ".", "-root", root, "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generating the spec: %v\n%s", err, output)
+	}
+
+	generated, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(committed, generated) {
+		t.Fatal("internal/docs/openapi.json is out of date, run go generate ./internal/docs")
+	}
+}
+
+// TestDocsMatchRoutes fails when a route has no operation in the spec, or an
+// operation in the spec has no route
+func TestDocsMatchRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := NewServer(validation.New(), memory.New(), nil, nil, idgen.NewRandom(), bloom.Disabled())
+	if err := s.MapRoutes(); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := map[string]bool{}
+	for _, route := range s.GetEngine().Routes() {
+		if strings.HasPrefix(route.Path, "/api/docs") {
+			continue
+		}
+		routes[route.Method+" "+pathParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+
+	spec, err := docs.FS.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err = json.Unmarshal(spec, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	operations := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method := range item {
+			operations[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range routes {
+		if !operations[route] {
+			t.Errorf("route %s is not documented", route)
+		}
+	}
+	for operation := range operations {
+		if !routes[operation] {
+			t.Errorf("documented operation %s has no route", operation)
+		}
+	}
+
+	if len(routes) == 0 || !routes[http.MethodPost+" /api/v1/create"] {
+		t.Fatal("routes were not mapped")
+	}
+}
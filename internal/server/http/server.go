@@ -1,51 +1,85 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.elastic.co/apm/module/apmgin/v2"
 
+	apiKeysHttp "shortbin/internal/apikeys/http"
+	apiKeysService "shortbin/internal/apikeys/service"
 	authHttp "shortbin/internal/auth/http"
 	createHttp "shortbin/internal/create/http"
+	docsHttp "shortbin/internal/docs/http"
+	linksHttp "shortbin/internal/links/http"
+	plansHttp "shortbin/internal/plans/http"
 	retrieveHttp "shortbin/internal/retrieve/http"
+	statsHttp "shortbin/internal/stats/http"
+	"shortbin/internal/storage"
+	"shortbin/pkg/bloom"
 	"shortbin/pkg/config"
+	"shortbin/pkg/events"
+	"shortbin/pkg/idgen"
 	"shortbin/pkg/kafka"
 	"shortbin/pkg/logger"
+	"shortbin/pkg/ratelimit"
 	"shortbin/pkg/redis"
 	"shortbin/pkg/validation"
 )
 
+const (
+	defaultReadTimeout     = 10 * time.Second
+	defaultWriteTimeout    = 30 * time.Second
+	defaultIdleTimeout     = 120 * time.Second
+	defaultShutdownTimeout = 20 * time.Second
+
+	// one flushShare-th of the shutdown timeout is reserved for flushing the
+	// Kafka producer, so slow requests can not use up all of it
+	flushShare = 3
+)
+
 type Server struct {
 	engine    *gin.Engine
 	cfg       *config.Config
 	validator validation.Validation
-	db        *pgxpool.Pool
+	store     storage.Store
 	kp        kafka.IKafkaProducer
 	cache     redis.IRedis
+	idGen     idgen.IDGenerator
+	filter    bloom.Filter
 }
 
 func NewServer(
 	validator validation.Validation,
-	db *pgxpool.Pool,
+	store storage.Store,
 	kp kafka.IKafkaProducer,
 	cache redis.IRedis,
+	idGen idgen.IDGenerator,
+	filter bloom.Filter,
 ) *Server {
 	return &Server{
 		engine:    gin.Default(),
 		cfg:       config.GetConfig(),
 		validator: validator,
-		db:        db,
+		store:     store,
 		kp:        kp,
 		cache:     cache,
+		idGen:     idGen,
+		filter:    filter,
 	}
 }
 
-func (s Server) Run() error {
+// Run serves HTTP until ctx is done or the listener fails, then shuts down:
+// it stops accepting connections and drains in-flight requests, flushes the
+// Kafka producer and finally closes Redis and the store
+func (s Server) Run(ctx context.Context) error {
 	_ = s.engine.SetTrustedProxies(nil)
 	if s.cfg.Environment == config.ProductionEnv {
 		gin.SetMode(gin.ReleaseMode)
@@ -54,6 +88,8 @@ func (s Server) Run() error {
 	if s.cfg.EnablePprof {
 		pprof.Register(s.engine)
 	}
+	// the producer, outbox and cache metrics, served without pprof
+	s.engine.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 
 	s.engine.Use(apmgin.Middleware(s.engine))
 
@@ -65,13 +101,72 @@ func (s Server) Run() error {
 		c.JSON(http.StatusOK, gin.H{"status": "online"})
 	})
 
+	timeouts := s.cfg.HTTPTimeouts
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.cfg.HTTPPort),
+		Handler:           s.engine,
+		ReadHeaderTimeout: withDefault(timeouts.Read, defaultReadTimeout),
+		ReadTimeout:       withDefault(timeouts.Read, defaultReadTimeout),
+		WriteTimeout:      withDefault(timeouts.Write, defaultWriteTimeout),
+		IdleTimeout:       withDefault(timeouts.Idle, defaultIdleTimeout),
+	}
+
 	// Start http server
-	logger.Info("HTTP server is listening on port ", s.cfg.HTTPPort)
-	if err := s.engine.Run(fmt.Sprintf(":%d", s.cfg.HTTPPort)); err != nil {
-		log.Fatalf("Running HTTP server: %v", err)
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("HTTP server is listening on port ", s.cfg.HTTPPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	var runErr error
+	select {
+	case err := <-serveErr:
+		runErr = fmt.Errorf("running HTTP server: %w", err)
+	case <-ctx.Done():
+		logger.Info("Shutting down HTTP server")
 	}
 
-	return nil
+	s.shutdown(srv, withDefault(timeouts.Shutdown, defaultShutdownTimeout))
+	return runErr
+}
+
+// shutdown drains in-flight requests until the share of timeout reserved for
+// the flush is left, then flushes the producer until timeout ends. Time the
+// drain does not use is left to the flush.
+func (s Server) shutdown(srv *http.Server, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), timeout-timeout/flushShare)
+	defer cancelDrain()
+
+	if err := srv.Shutdown(drainCtx); err != nil {
+		logger.Error("Failed to drain HTTP connections ", err)
+	}
+
+	flushCtx, cancelFlush := context.WithDeadline(context.Background(), deadline)
+	defer cancelFlush()
+
+	if err := s.kp.Close(flushCtx); err != nil {
+		logger.Error("Failed to flush kafka producer ", err)
+	}
+
+	if err := s.cache.Close(); err != nil {
+		logger.Error("Failed to close redis ", err)
+	}
+
+	s.store.Close()
+	logger.Info("Shutdown complete")
+}
+
+// withDefault converts a timeout in seconds from config, using fallback when unset
+func withDefault(seconds time.Duration, fallback time.Duration) time.Duration {
+	if seconds <= 0 {
+		return fallback
+	}
+	return seconds * time.Second
 }
 
 func (s Server) GetEngine() *gin.Engine {
@@ -81,9 +176,25 @@ func (s Server) GetEngine() *gin.Engine {
 func (s Server) MapRoutes() error {
 	v1 := s.engine.Group("/api/v1")
 
-	retrieveHttp.Routes(s.engine, s.db, s.kp, s.cache)
-	authHttp.Routes(v1, s.db, s.validator)
-	createHttp.Routes(v1, s.db, s.validator)
+	clickEncoder, err := kafka.NewEncoder[events.ClickEvent](s.cfg.Kafka.Encoding)
+	if err != nil {
+		return err
+	}
+	clicks := kafka.NewTypedProducer(s.kp, clickEncoder)
+
+	limiter := ratelimit.New(s.cache, s.cfg.RateLimit.MemorySize)
+
+	// the authenticated routes accept API keys in place of access tokens
+	apiKeys := apiKeysService.NewAPIKeysService(s.validator, s.store.APIKeys())
+
+	retrieveHttp.Routes(s.engine, s.store, clicks, s.cache, s.filter, limiter)
+	authHttp.Routes(v1, s.store, s.validator, limiter)
+	createHttp.Routes(v1, s.store, s.validator, s.idGen, s.cache, s.filter, limiter, apiKeys)
+	linksHttp.Routes(v1, s.store, s.validator, s.cache, apiKeys)
+	statsHttp.Routes(v1, s.store, s.validator, apiKeys)
+	plansHttp.Routes(v1, s.store, apiKeys)
+	apiKeysHttp.Routes(v1, s.store, s.validator)
+	docsHttp.Routes(s.engine)
 
 	return nil
 }
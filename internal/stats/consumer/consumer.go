@@ -0,0 +1,89 @@
+package consumer
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"shortbin/internal/stats/model"
+	"shortbin/internal/stats/repository"
+	"shortbin/pkg/events"
+)
+
+const (
+	directReferrer   = "direct"
+	unknownValue     = "unknown"
+	maxUserAgentSize = 256
+)
+
+// ClickConsumer aggregates click events from the clicks topics into the
+// per-link statistics tables
+type ClickConsumer struct {
+	repo repository.IClicksRepository
+}
+
+func NewClickConsumer(repo repository.IClicksRepository) *ClickConsumer {
+	return &ClickConsumer{
+		repo: repo,
+	}
+}
+
+// Handle aggregates one click event, it is wrapped with kafka.TypedHandler.
+// Events predating schema version 1 have no occurred_at and fall back to
+// the message timestamp.
+func (c *ClickConsumer) Handle(ctx context.Context, key string, event *events.ClickEvent, timestamp time.Time) error {
+	shortID := event.ShortID
+	if shortID == "" {
+		shortID = key
+	}
+
+	click := model.Click{
+		EventID:   event.EventID,
+		ShortID:   shortID,
+		Referrer:  referrerHost(event.Referer),
+		UserAgent: userAgent(event.UserAgent),
+		Country:   country(event.Country),
+		ClickedAt: event.OccurredAt,
+	}
+	if click.ClickedAt.IsZero() {
+		click.ClickedAt = timestamp
+	}
+	if click.ClickedAt.IsZero() {
+		click.ClickedAt = time.Now()
+	}
+
+	return c.repo.Record(ctx, &click)
+}
+
+// referrerHost keeps only the host of the referrer to bound cardinality
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return directReferrer
+	}
+
+	u, err := url.Parse(referrer)
+	if err != nil || u.Hostname() == "" {
+		return unknownValue
+	}
+
+	return strings.ToLower(u.Hostname())
+}
+
+func userAgent(ua string) string {
+	if ua == "" {
+		return unknownValue
+	}
+	if len(ua) > maxUserAgentSize {
+		return ua[:maxUserAgentSize]
+	}
+	return ua
+}
+
+func country(code string) string {
+	// XX and T1 are sent by Cloudflare for unknown and Tor visitors
+	if len(code) != 2 || code == "XX" || code == "T1" {
+		return unknownValue
+	}
+	return strings.ToUpper(code)
+}
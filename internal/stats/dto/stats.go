@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+)
+
+type StatsReq struct {
+	Days int `form:"days" validate:"omitempty,min=1,max=365"`
+	Top  int `form:"top" validate:"omitempty,min=1,max=100"`
+}
+
+type DailyClicks struct {
+	Day    time.Time `json:"day"`
+	Clicks int64     `json:"clicks"`
+}
+
+type Count struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
+type StatsRes struct {
+	ShortID     string        `json:"short_id"`
+	TotalClicks int64         `json:"total_clicks"`
+	Daily       []DailyClicks `json:"daily"`
+	Referrers   []Count       `json:"referrers"`
+	UserAgents  []Count       `json:"user_agents"`
+	Countries   []Count       `json:"countries"`
+}
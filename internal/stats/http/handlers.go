@@ -0,0 +1,67 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/stats/dto"
+	"shortbin/internal/stats/service"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+	"shortbin/pkg/utils"
+)
+
+type StatsHandler struct {
+	service service.IStatsService
+}
+
+func NewStatsHandler(service service.IStatsService) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+	}
+}
+
+// GetStats godoc
+//
+//	@Summary	Click statistics of one of my links
+//	@Tags		links
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		short_id	path		string	true	"Short ID"
+//	@Param		days		query		int		false	"Number of days of daily clicks, at most 365"
+//	@Param		top			query		int		false	"Number of top referrers, user agents and countries, at most 100"
+//	@Success	200			{object}	dto.StatsRes
+//	@Failure	404			{object}	response.ErrorResponse	"id not found"
+//	@Router		/api/v1/links/{short_id}/stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	var req dto.StatsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query ", err)
+		response.Error(c, http.StatusBadRequest, err, response.InvalidParameters)
+		return
+	}
+
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(response.EmptyUserID), response.Unauthorized)
+		return
+	}
+
+	stats, err := h.service.GetStats(c.Request.Context(), userID, c.Param("short_id"), &req)
+	if err != nil {
+		if err.Error() == response.IDNotFound {
+			response.Error(c, http.StatusNotFound, err, response.IDNotFound)
+			return
+		}
+
+		logger.Error(err.Error())
+		response.Error(c, http.StatusInternalServerError, err, response.SomethingWentWrong)
+		return
+	}
+
+	var res dto.StatsRes
+	utils.Copy(&res, &stats)
+	response.JSON(c, http.StatusOK, res)
+}
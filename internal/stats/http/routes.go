@@ -0,0 +1,19 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"shortbin/internal/stats/service"
+	"shortbin/internal/storage"
+	"shortbin/pkg/middleware"
+	"shortbin/pkg/validation"
+)
+
+func Routes(r *gin.RouterGroup, store storage.Store, validator validation.Validation, apiKeys middleware.APIKeyValidator) {
+	statsRepo := store.Stats()
+	statsSvc := service.NewStatsService(validator, statsRepo)
+	statsHandler := NewStatsHandler(statsSvc)
+
+	authMiddleware := middleware.JWTAuth(apiKeys)
+	r.GET("/links/:short_id/stats", authMiddleware, middleware.RequireScope(middleware.ScopeStats), statsHandler.GetStats)
+}
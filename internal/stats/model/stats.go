@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+// Click is a single redirect as read from the clicks topics
+type Click struct {
+	// EventID is empty for events predating schema version 1
+	EventID   string
+	ShortID   string
+	Referrer  string
+	UserAgent string
+	Country   string
+	ClickedAt time.Time
+}
+
+// DailyClicks number of clicks of a link on one day (UTC)
+type DailyClicks struct {
+	Day    time.Time `json:"day"`
+	Clicks int64     `json:"clicks"`
+}
+
+// Count number of clicks for one value of a dimension such as the referrer
+type Count struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
+// Stats aggregated click statistics of a link
+type Stats struct {
+	ShortID     string        `json:"short_id"`
+	TotalClicks int64         `json:"total_clicks"`
+	Daily       []DailyClicks `json:"daily"`
+	Referrers   []Count       `json:"referrers"`
+	UserAgents  []Count       `json:"user_agents"`
+	Countries   []Count       `json:"countries"`
+}
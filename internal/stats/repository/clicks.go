@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"shortbin/internal/stats/model"
+)
+
+type IClicksRepository interface {
+	Record(ctx context.Context, click *model.Click) error
+}
+
+type ClicksRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewClicksRepository(db *pgxpool.Pool) *ClicksRepo {
+	return &ClicksRepo{db: db}
+}
+
+// Record adds a click to every aggregate in one transaction. A click whose
+// event was recorded before is skipped, so redelivered events count once.
+func (r *ClicksRepo) Record(ctx context.Context, click *model.Click) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if click.EventID != "" {
+			tag, err := tx.Exec(ctx, `INSERT INTO processed_click_events (event_id) VALUES ($1)
+				ON CONFLICT (event_id) DO NOTHING`, click.EventID)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return nil
+			}
+		}
+
+		batch := &pgx.Batch{}
+		batch.Queue(`INSERT INTO link_clicks_daily (short_id, day, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, day) DO UPDATE SET clicks = link_clicks_daily.clicks + 1`,
+			click.ShortID, click.ClickedAt.UTC().Truncate(24*time.Hour))
+		batch.Queue(`INSERT INTO link_referrers (short_id, referrer, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, referrer) DO UPDATE SET clicks = link_referrers.clicks + 1`,
+			click.ShortID, click.Referrer)
+		batch.Queue(`INSERT INTO link_user_agents (short_id, user_agent, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, user_agent) DO UPDATE SET clicks = link_user_agents.clicks + 1`,
+			click.ShortID, click.UserAgent)
+		batch.Queue(`INSERT INTO link_countries (short_id, country, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, country) DO UPDATE SET clicks = link_countries.clicks + 1`,
+			click.ShortID, click.Country)
+
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+// aggregateTables hold the statistics of a link, keyed by its short ID
+var aggregateTables = []string{"link_clicks_daily", "link_referrers", "link_user_agents", "link_countries"}
+
+// DeleteStats removes the statistics of deleted links in tx, so that a short
+// ID created again later does not inherit the clicks of the old link
+func DeleteStats(ctx context.Context, tx pgx.Tx, shortIDs []string) error {
+	if len(shortIDs) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, table := range aggregateTables {
+		batch.Queue(`DELETE FROM `+table+` WHERE short_id = ANY($1)`, shortIDs)
+	}
+
+	return tx.SendBatch(ctx, batch).Close()
+}
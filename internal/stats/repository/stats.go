@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/stats/model"
+	"shortbin/pkg/response"
+)
+
+type IStatsRepository interface {
+	IsOwner(ctx context.Context, userID string, shortID string) error
+	GetDailyClicks(ctx context.Context, shortID string, since time.Time) ([]model.DailyClicks, error)
+	GetTotalClicks(ctx context.Context, shortID string) (int64, error)
+	GetTopReferrers(ctx context.Context, shortID string, limit int) ([]model.Count, error)
+	GetTopUserAgents(ctx context.Context, shortID string, limit int) ([]model.Count, error)
+	GetTopCountries(ctx context.Context, shortID string, limit int) ([]model.Count, error)
+}
+
+type StatsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStatsRepository(db *pgxpool.Pool) *StatsRepo {
+	return &StatsRepo{db: db}
+}
+
+func (r *StatsRepo) IsOwner(ctx context.Context, userID string, shortID string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsRepo.IsOwner", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT 1 FROM urls WHERE short_id=$1 AND user_id=$2`
+
+	var one int
+	if err := r.db.QueryRow(ctx, query, shortID, userID).Scan(&one); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New(response.IDNotFound)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *StatsRepo) GetDailyClicks(ctx context.Context, shortID string, since time.Time) ([]model.DailyClicks, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsRepo.GetDailyClicks", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT day, clicks FROM link_clicks_daily WHERE short_id=$1 AND day >= $2 ORDER BY day`
+
+	rows, err := r.db.Query(ctx, query, shortID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.DailyClicks, error) {
+		var daily model.DailyClicks
+		err := row.Scan(&daily.Day, &daily.Clicks)
+		return daily, err
+	})
+}
+
+func (r *StatsRepo) GetTotalClicks(ctx context.Context, shortID string) (int64, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsRepo.GetTotalClicks", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT COALESCE(SUM(clicks), 0) FROM link_clicks_daily WHERE short_id=$1`
+
+	var total int64
+	err := r.db.QueryRow(ctx, query, shortID).Scan(&total)
+	return total, err
+}
+
+func (r *StatsRepo) GetTopReferrers(ctx context.Context, shortID string, limit int) ([]model.Count, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsRepo.GetTopReferrers", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT referrer, clicks FROM link_referrers WHERE short_id=$1 ORDER BY clicks DESC LIMIT $2`
+	return r.getCounts(ctx, query, shortID, limit)
+}
+
+func (r *StatsRepo) GetTopUserAgents(ctx context.Context, shortID string, limit int) ([]model.Count, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsRepo.GetTopUserAgents", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT user_agent, clicks FROM link_user_agents WHERE short_id=$1 ORDER BY clicks DESC LIMIT $2`
+	return r.getCounts(ctx, query, shortID, limit)
+}
+
+func (r *StatsRepo) GetTopCountries(ctx context.Context, shortID string, limit int) ([]model.Count, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsRepo.GetTopCountries", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT country, clicks FROM link_countries WHERE short_id=$1 ORDER BY clicks DESC LIMIT $2`
+	return r.getCounts(ctx, query, shortID, limit)
+}
+
+func (r *StatsRepo) getCounts(ctx context.Context, query string, shortID string, limit int) ([]model.Count, error) {
+	rows, err := r.db.Query(ctx, query, shortID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Count, error) {
+		var count model.Count
+		err := row.Scan(&count.Value, &count.Clicks)
+		return count, err
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.elastic.co/apm/module/apmzap/v2"
+	"go.elastic.co/apm/v2"
+	"go.uber.org/zap"
+
+	"shortbin/internal/stats/dto"
+	"shortbin/internal/stats/model"
+	"shortbin/internal/stats/repository"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/response"
+	"shortbin/pkg/validation"
+)
+
+const (
+	defaultDays = 30
+	defaultTop  = 10
+)
+
+//go:generate mockery --name=IStatsService
+type IStatsService interface {
+	GetStats(ctx context.Context, userID string, shortID string, req *dto.StatsReq) (*model.Stats, error)
+}
+
+type StatsService struct {
+	validator validation.Validation
+	repo      repository.IStatsRepository
+}
+
+func NewStatsService(
+	validator validation.Validation,
+	repo repository.IStatsRepository) *StatsService {
+	return &StatsService{
+		validator: validator,
+		repo:      repo,
+	}
+}
+
+func (s *StatsService) GetStats(ctx context.Context, userID string, shortID string, req *dto.StatsReq) (*model.Stats, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	rootSpan, ctx := apm.StartSpan(ctx, "*StatsService.GetStats", "service")
+	traceContextFields := apmzap.TraceContext(ctx)
+	defer rootSpan.End()
+
+	if err := s.repo.IsOwner(ctx, userID, shortID); err != nil {
+		if err.Error() != response.IDNotFound {
+			logger.Infof("GetStats.IsOwner fail, short_id: %s, error: %s", shortID, err)
+			logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+		}
+		return nil, err
+	}
+
+	days, top := req.Days, req.Top
+	if days == 0 {
+		days = defaultDays
+	}
+	if top == 0 {
+		top = defaultTop
+	}
+
+	stats := model.Stats{ShortID: shortID}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+
+	var err error
+	if stats.TotalClicks, err = s.repo.GetTotalClicks(ctx, shortID); err != nil {
+		return nil, s.logError(traceContextFields, shortID, err)
+	}
+	if stats.Daily, err = s.repo.GetDailyClicks(ctx, shortID, since); err != nil {
+		return nil, s.logError(traceContextFields, shortID, err)
+	}
+	if stats.Referrers, err = s.repo.GetTopReferrers(ctx, shortID, top); err != nil {
+		return nil, s.logError(traceContextFields, shortID, err)
+	}
+	if stats.UserAgents, err = s.repo.GetTopUserAgents(ctx, shortID, top); err != nil {
+		return nil, s.logError(traceContextFields, shortID, err)
+	}
+	if stats.Countries, err = s.repo.GetTopCountries(ctx, shortID, top); err != nil {
+		return nil, s.logError(traceContextFields, shortID, err)
+	}
+
+	return &stats, nil
+}
+
+func (s *StatsService) logError(traceContextFields []zap.Field, shortID string, err error) error {
+	logger.Infof("GetStats fail, short_id: %s, error: %s", shortID, err)
+	logger.ApmLogger.With(traceContextFields...).Error(err.Error())
+	return err
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shortbin/internal/apikeys/model"
+	"shortbin/pkg/database"
+	"shortbin/pkg/response"
+)
+
+type APIKeysRepo struct {
+	s *Store
+}
+
+func (r *APIKeysRepo) Create(_ context.Context, key *model.APIKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, stored := range r.s.apiKeys {
+		if stored.HashedKey == key.HashedKey {
+			return database.ErrUniqueViolation
+		}
+	}
+
+	key.ID, key.CreatedAt = uuid.NewString(), time.Now().UTC()
+	r.s.apiKeys[key.ID] = *key
+
+	return nil
+}
+
+func (r *APIKeysRepo) List(_ context.Context, userID string) ([]*model.APIKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	keys := []*model.APIKey{}
+	for _, key := range r.s.apiKeys {
+		if key.UserID == userID {
+			key.HashedKey = ""
+			keys = append(keys, &key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeysRepo) Delete(_ context.Context, userID string, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key, ok := r.s.apiKeys[id]
+	if !ok || key.UserID != userID {
+		return errors.New(response.IDNotFound)
+	}
+	delete(r.s.apiKeys, id)
+
+	return nil
+}
+
+func (r *APIKeysRepo) GetByHash(_ context.Context, hashedKey string) (*model.APIKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, key := range r.s.apiKeys {
+		if key.HashedKey == hashedKey {
+			key.HashedKey = ""
+			return &key, nil
+		}
+	}
+
+	return nil, errors.New(response.InvalidAPIKey)
+}
+
+// Touch records that the key was just used
+func (r *APIKeysRepo) Touch(_ context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if key, ok := r.s.apiKeys[id]; ok {
+		now := time.Now().UTC()
+		key.LastUsedAt = &now
+		r.s.apiKeys[id] = key
+	}
+
+	return nil
+}
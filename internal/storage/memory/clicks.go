@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"shortbin/internal/stats/model"
+)
+
+type ClicksRepo struct {
+	s *Store
+}
+
+// Record adds a click to every aggregate, unless its event was recorded before
+func (r *ClicksRepo) Record(_ context.Context, click *model.Click) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if click.EventID != "" {
+		if _, ok := r.s.processed[click.EventID]; ok {
+			return nil
+		}
+		r.s.processed[click.EventID] = struct{}{}
+	}
+
+	r.s.clicks[dayKey{id: click.ShortID, day: click.ClickedAt.UTC().Truncate(24 * time.Hour)}]++
+	r.s.counts[referrers][countKey{shortID: click.ShortID, value: click.Referrer}]++
+	r.s.counts[userAgents][countKey{shortID: click.ShortID, value: click.UserAgent}]++
+	r.s.counts[countries][countKey{shortID: click.ShortID, value: click.Country}]++
+
+	return nil
+}
+
+// deleteStats removes the statistics of a deleted link, so that a short ID
+// created again later does not inherit the clicks of the old link. The caller
+// holds s.mu.
+func (s *Store) deleteStats(shortID string) {
+	for key := range s.clicks {
+		if key.id == shortID {
+			delete(s.clicks, key)
+		}
+	}
+	for _, counts := range s.counts {
+		for key := range counts {
+			if key.shortID == shortID {
+				delete(counts, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+
+	"shortbin/internal/common/model"
+	"shortbin/pkg/database"
+)
+
+type CreateRepo struct {
+	s *Store
+}
+
+func (r *CreateRepo) Create(_ context.Context, url *model.URL) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.urls[url.ShortID]; ok {
+		return database.ErrUniqueViolation
+	}
+	r.s.urls[url.ShortID] = *url
+
+	return nil
+}
+
+// CreateBatch skips urls whose short ID is taken, reporting false at their
+// index
+func (r *CreateRepo) CreateBatch(_ context.Context, urls []*model.URL) ([]bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	inserted := make([]bool, len(urls))
+	for i, url := range urls {
+		if _, ok := r.s.urls[url.ShortID]; ok {
+			continue
+		}
+		r.s.urls[url.ShortID] = *url
+		inserted[i] = true
+	}
+
+	return inserted, nil
+}
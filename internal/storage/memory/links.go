@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"shortbin/internal/common/model"
+	"shortbin/internal/links/repository"
+	"shortbin/pkg/response"
+)
+
+type LinksRepo struct {
+	s *Store
+}
+
+func (r *LinksRepo) List(_ context.Context, userID string, after *repository.Cursor, limit int) ([]*model.URL, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var urls []*model.URL
+	for _, url := range r.s.urls {
+		if !ownedBy(&url, userID) {
+			continue
+		}
+		if after != nil && !before(&url, after) {
+			continue
+		}
+		url.HashedPassword = nil
+		urls = append(urls, &url)
+	}
+
+	// newest first, like the (created_at, short_id) DESC order of the
+	// SQL stores
+	sort.Slice(urls, func(i, j int) bool {
+		return before(urls[j], &repository.Cursor{CreatedAt: urls[i].CreatedAt, ShortID: urls[i].ShortID})
+	})
+
+	if len(urls) > limit {
+		urls = urls[:limit]
+	}
+
+	return urls, nil
+}
+
+func (r *LinksRepo) GetByID(_ context.Context, userID string, shortID string) (*model.URL, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	url, ok := r.s.urls[shortID]
+	if !ok || !ownedBy(&url, userID) {
+		return nil, errors.New(response.IDNotFound)
+	}
+
+	url.HashedPassword = nil
+	return &url, nil
+}
+
+func (r *LinksRepo) Update(_ context.Context, url *model.URL) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.urls[url.ShortID]
+	if !ok || url.UserID == nil || !ownedBy(&stored, *url.UserID) {
+		return errors.New(response.IDNotFound)
+	}
+
+	stored.LongURL, stored.RedirectType, stored.ExpiresAt = url.LongURL, url.RedirectType, url.ExpiresAt
+	r.s.urls[url.ShortID] = stored
+
+	return nil
+}
+
+func (r *LinksRepo) Delete(_ context.Context, userID string, shortID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	url, ok := r.s.urls[shortID]
+	if !ok || !ownedBy(&url, userID) {
+		return errors.New(response.IDNotFound)
+	}
+	delete(r.s.urls, shortID)
+	r.s.deleteStats(shortID)
+
+	return nil
+}
+
+func ownedBy(url *model.URL, userID string) bool {
+	return url.UserID != nil && *url.UserID == userID
+}
+
+// before reports whether url comes after the cursor in (created_at,
+// short_id) descending order
+func before(url *model.URL, cursor *repository.Cursor) bool {
+	if !url.CreatedAt.Equal(cursor.CreatedAt) {
+		return url.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return url.ShortID < cursor.ShortID
+}
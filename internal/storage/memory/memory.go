@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apiKeysModel "shortbin/internal/apikeys/model"
+	apiKeysRepository "shortbin/internal/apikeys/repository"
+	authModel "shortbin/internal/auth/model"
+	authRepository "shortbin/internal/auth/repository"
+	commonModel "shortbin/internal/common/model"
+	createRepository "shortbin/internal/create/repository"
+	linksRepository "shortbin/internal/links/repository"
+	plansModel "shortbin/internal/plans/model"
+	plansRepository "shortbin/internal/plans/repository"
+	retrieveRepository "shortbin/internal/retrieve/repository"
+	statsRepository "shortbin/internal/stats/repository"
+	sweeperRepository "shortbin/internal/sweeper/repository"
+)
+
+// dimensions of the click counts besides the day
+const (
+	referrers  = "referrer"
+	userAgents = "user_agent"
+	countries  = "country"
+)
+
+type dayKey struct {
+	id  string
+	day time.Time
+}
+
+type countKey struct {
+	shortID string
+	value   string
+}
+
+// Store keeps everything in maps guarded by one mutex, for tests and trying
+// shortbin out. Nothing survives a restart.
+type Store struct {
+	mu        sync.Mutex
+	urls      map[string]commonModel.URL
+	archive   []commonModel.URL
+	users     map[string]authModel.User
+	userPlans map[string]string
+	plans     map[string]plansModel.Plan
+	usage     map[dayKey]int
+	apiKeys   map[string]apiKeysModel.APIKey
+	clicks    map[dayKey]int64
+	counts    map[string]map[countKey]int64
+	processed map[string]struct{}
+}
+
+// New empty Store with the default plans
+func New() *Store {
+	plans := []plansModel.Plan{
+		{Name: "free", DailyQuota: 50, MonthlyQuota: 500, MaxExpiryDays: 365},
+		{Name: "pro", DailyQuota: 1000, MonthlyQuota: 20000, MaxExpiryDays: 1825, CustomAliases: true, PasswordLinks: true},
+		{Name: "enterprise", CustomAliases: true, PasswordLinks: true},
+		{Name: "anonymous", CustomAliases: true, PasswordLinks: true},
+	}
+
+	s := &Store{
+		urls:      make(map[string]commonModel.URL),
+		users:     make(map[string]authModel.User),
+		userPlans: make(map[string]string),
+		plans:     make(map[string]plansModel.Plan, len(plans)),
+		usage:     make(map[dayKey]int),
+		apiKeys:   make(map[string]apiKeysModel.APIKey),
+		clicks:    make(map[dayKey]int64),
+		counts: map[string]map[countKey]int64{
+			referrers:  make(map[countKey]int64),
+			userAgents: make(map[countKey]int64),
+			countries:  make(map[countKey]int64),
+		},
+		processed: make(map[string]struct{}),
+	}
+	for _, plan := range plans {
+		s.plans[plan.Name] = plan
+	}
+
+	return s
+}
+
+func (s *Store) Create() createRepository.ICreateRepository {
+	return &CreateRepo{s: s}
+}
+
+func (s *Store) Retrieve() retrieveRepository.IRetrieveRepository {
+	return &RetrieveRepo{s: s}
+}
+
+func (s *Store) Users() authRepository.IUserRepository {
+	return &UserRepo{s: s}
+}
+
+func (s *Store) Links() linksRepository.ILinksRepository {
+	return &LinksRepo{s: s}
+}
+
+func (s *Store) Stats() statsRepository.IStatsRepository {
+	return &StatsRepo{s: s}
+}
+
+func (s *Store) Clicks() statsRepository.IClicksRepository {
+	return &ClicksRepo{s: s}
+}
+
+func (s *Store) Plans() plansRepository.IPlansRepository {
+	return &PlansRepo{s: s}
+}
+
+func (s *Store) APIKeys() apiKeysRepository.IAPIKeysRepository {
+	return &APIKeysRepo{s: s}
+}
+
+func (s *Store) Sweeper() sweeperRepository.ISweeperRepository {
+	return &SweeperRepo{s: s}
+}
+
+// BlockedDomains is always empty, there is no way to add any
+func (s *Store) BlockedDomains(_ context.Context) ([]string, error) {
+	return []string{}, nil
+}
+
+func (s *Store) Close() {}
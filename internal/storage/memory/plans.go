@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"shortbin/internal/plans/model"
+	"shortbin/pkg/response"
+)
+
+type PlansRepo struct {
+	s *Store
+}
+
+func (r *PlansRepo) GetPlanByName(_ context.Context, name string) (*model.Plan, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	plan, ok := r.s.plans[name]
+	if !ok {
+		return nil, errors.New(response.PlanNotFound)
+	}
+
+	return &plan, nil
+}
+
+func (r *PlansRepo) GetPlanByUserID(_ context.Context, userID string) (*model.Plan, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	plan, ok := r.s.plans[r.s.userPlans[userID]]
+	if !ok {
+		return nil, errors.New(response.PlanNotFound)
+	}
+
+	return &plan, nil
+}
+
+// GetUsage returns the number of links created by the user on day and since
+// monthStart
+func (r *PlansRepo) GetUsage(_ context.Context, userID string, day time.Time, monthStart time.Time) (int, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var daily, monthly int
+	for key, created := range r.s.usage {
+		if key.id != userID || key.day.Before(monthStart) {
+			continue
+		}
+		if key.day.Equal(day) {
+			daily += created
+		}
+		monthly += created
+	}
+
+	return daily, monthly, nil
+}
+
+// Reserve counts count more links created by the user on day, unless that
+// would exceed dailyQuota. A zero dailyQuota is unlimited.
+func (r *PlansRepo) Reserve(_ context.Context, userID string, day time.Time, dailyQuota int, count int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := dayKey{id: userID, day: day.UTC()}
+	if dailyQuota > 0 && r.s.usage[key]+count > dailyQuota {
+		return false, nil
+	}
+	r.s.usage[key] += count
+
+	return true, nil
+}
+
+// Release gives back reservations for links that were not created after all
+func (r *PlansRepo) Release(_ context.Context, userID string, day time.Time, count int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := dayKey{id: userID, day: day.UTC()}
+	if created, ok := r.s.usage[key]; ok {
+		r.s.usage[key] = max(created-count, 0)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"shortbin/internal/common/model"
+)
+
+type RetrieveRepo struct {
+	s *Store
+}
+
+func (r *RetrieveRepo) GetURLByID(_ context.Context, id string) (*model.URL, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	url, ok := r.s.urls[id]
+	if !ok {
+		return nil, errors.New("id not found")
+	}
+
+	return &url, nil
+}
+
+// ListShortIDs returns up to limit short IDs ordered after the given one
+func (r *RetrieveRepo) ListShortIDs(_ context.Context, after string, limit int) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := []string{}
+	for id := range r.s.urls {
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	return ids, nil
+}
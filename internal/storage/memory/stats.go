@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"shortbin/internal/stats/model"
+	"shortbin/pkg/response"
+)
+
+type StatsRepo struct {
+	s *Store
+}
+
+func (r *StatsRepo) IsOwner(_ context.Context, userID string, shortID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	url, ok := r.s.urls[shortID]
+	if !ok || !ownedBy(&url, userID) {
+		return errors.New(response.IDNotFound)
+	}
+
+	return nil
+}
+
+func (r *StatsRepo) GetDailyClicks(_ context.Context, shortID string, since time.Time) ([]model.DailyClicks, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	daily := []model.DailyClicks{}
+	for key, clicks := range r.s.clicks {
+		if key.id == shortID && !key.day.Before(since) {
+			daily = append(daily, model.DailyClicks{Day: key.day, Clicks: clicks})
+		}
+	}
+	sort.Slice(daily, func(i, j int) bool {
+		return daily[i].Day.Before(daily[j].Day)
+	})
+
+	return daily, nil
+}
+
+func (r *StatsRepo) GetTotalClicks(_ context.Context, shortID string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var total int64
+	for key, clicks := range r.s.clicks {
+		if key.id == shortID {
+			total += clicks
+		}
+	}
+
+	return total, nil
+}
+
+func (r *StatsRepo) GetTopReferrers(_ context.Context, shortID string, limit int) ([]model.Count, error) {
+	return r.getCounts(referrers, shortID, limit), nil
+}
+
+func (r *StatsRepo) GetTopUserAgents(_ context.Context, shortID string, limit int) ([]model.Count, error) {
+	return r.getCounts(userAgents, shortID, limit), nil
+}
+
+func (r *StatsRepo) GetTopCountries(_ context.Context, shortID string, limit int) ([]model.Count, error) {
+	return r.getCounts(countries, shortID, limit), nil
+}
+
+func (r *StatsRepo) getCounts(dimension string, shortID string, limit int) []model.Count {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	counts := []model.Count{}
+	for key, clicks := range r.s.counts[dimension] {
+		if key.shortID == shortID {
+			counts = append(counts, model.Count{Value: key.value, Clicks: clicks})
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Clicks > counts[j].Clicks
+	})
+
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+
+	return counts
+}
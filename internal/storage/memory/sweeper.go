@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+type SweeperRepo struct {
+	s *Store
+}
+
+func (r *SweeperRepo) DeleteExpired(_ context.Context, before time.Time, limit int) (int64, error) {
+	return r.sweep(before, limit, false), nil
+}
+
+func (r *SweeperRepo) ArchiveExpired(_ context.Context, before time.Time, limit int) (int64, error) {
+	return r.sweep(before, limit, true), nil
+}
+
+// sweep deletes up to limit links that expired before the given time
+func (r *SweeperRepo) sweep(before time.Time, limit int, archive bool) int64 {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var swept int64
+	for id, url := range r.s.urls {
+		if swept == int64(limit) {
+			break
+		}
+		if !url.ExpiresAt.Before(before) {
+			continue
+		}
+
+		if archive {
+			r.s.archive = append(r.s.archive, url)
+		}
+		delete(r.s.urls, id)
+		r.s.deleteStats(id)
+		swept++
+	}
+
+	return swept
+}
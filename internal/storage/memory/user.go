@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shortbin/internal/auth/model"
+	plansModel "shortbin/internal/plans/model"
+	"shortbin/pkg/database"
+)
+
+type UserRepo struct {
+	s *Store
+}
+
+func (r *UserRepo) Create(_ context.Context, email string, hashedPassword string) (*model.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.emailTaken(email, "") {
+		return nil, database.ErrUniqueViolation
+	}
+
+	user := model.User{
+		ID:             uuid.NewString(),
+		Email:          email,
+		HashedPassword: hashedPassword,
+		CreatedAt:      time.Now().UTC(),
+	}
+	r.s.users[user.ID] = user
+	r.s.userPlans[user.ID] = plansModel.DefaultPlan
+
+	return &user, nil
+}
+
+func (r *UserRepo) Update(_ context.Context, user *model.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.users[user.ID]
+	if !ok {
+		return nil
+	}
+	if r.emailTaken(user.Email, user.ID) {
+		return database.ErrUniqueViolation
+	}
+
+	stored.Email, stored.HashedPassword = user.Email, user.HashedPassword
+	r.s.users[user.ID] = stored
+
+	return nil
+}
+
+func (r *UserRepo) UpdatePassword(_ context.Context, userID string, hashedPassword string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.users[userID]; ok {
+		user.HashedPassword = hashedPassword
+		r.s.users[userID] = user
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetUserByID(_ context.Context, id string) (*model.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepo) GetUserByEmail(_ context.Context, email string) (*model.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// emailTaken by a user other than exceptID, the caller holds the lock
+func (r *UserRepo) emailTaken(email string, exceptID string) bool {
+	for id, user := range r.s.users {
+		if user.Email == email && id != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	apiKeysRepository "shortbin/internal/apikeys/repository"
+	authRepository "shortbin/internal/auth/repository"
+	createRepository "shortbin/internal/create/repository"
+	linksRepository "shortbin/internal/links/repository"
+	plansRepository "shortbin/internal/plans/repository"
+	retrieveRepository "shortbin/internal/retrieve/repository"
+	statsRepository "shortbin/internal/stats/repository"
+	sweeperRepository "shortbin/internal/sweeper/repository"
+)
+
+// Postgres store, the only one with migrations, the click outbox and the
+// postgres event sink, which use its Pool directly
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
+
+func (p *Postgres) Pool() *pgxpool.Pool {
+	return p.pool
+}
+
+func (p *Postgres) Create() createRepository.ICreateRepository {
+	return createRepository.NewCreateRepository(p.pool)
+}
+
+func (p *Postgres) Retrieve() retrieveRepository.IRetrieveRepository {
+	return retrieveRepository.NewRetrieveRepository(p.pool)
+}
+
+func (p *Postgres) Users() authRepository.IUserRepository {
+	return authRepository.NewUserRepository(p.pool)
+}
+
+func (p *Postgres) Links() linksRepository.ILinksRepository {
+	return linksRepository.NewLinksRepository(p.pool)
+}
+
+func (p *Postgres) Stats() statsRepository.IStatsRepository {
+	return statsRepository.NewStatsRepository(p.pool)
+}
+
+func (p *Postgres) Clicks() statsRepository.IClicksRepository {
+	return statsRepository.NewClicksRepository(p.pool)
+}
+
+func (p *Postgres) Plans() plansRepository.IPlansRepository {
+	return plansRepository.NewPlansRepository(p.pool)
+}
+
+func (p *Postgres) APIKeys() apiKeysRepository.IAPIKeysRepository {
+	return apiKeysRepository.NewAPIKeysRepository(p.pool)
+}
+
+func (p *Postgres) Sweeper() sweeperRepository.ISweeperRepository {
+	return sweeperRepository.NewSweeperRepository(p.pool)
+}
+
+func (p *Postgres) BlockedDomains(ctx context.Context) ([]string, error) {
+	rows, err := p.pool.Query(ctx, `SELECT domain FROM blocked_domains`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (p *Postgres) Close() {
+	p.pool.Close()
+}
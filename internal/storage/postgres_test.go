@@ -0,0 +1,69 @@
+//go:build postgres
+
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"shortbin/internal/storage"
+	"shortbin/migrations"
+	"shortbin/pkg/database"
+	"shortbin/pkg/migrate"
+)
+
+// TestPostgres runs the suite against the database in
+// SHORTBIN_TEST_DATA_SOURCE_NAME, every test in a schema of its own:
+//
+//	SHORTBIN_TEST_DATA_SOURCE_NAME=postgres://... go test -tags postgres ./internal/storage
+func TestPostgres(t *testing.T) {
+	dataSourceName := os.Getenv("SHORTBIN_TEST_DATA_SOURCE_NAME")
+	if dataSourceName == "" {
+		t.Skip("SHORTBIN_TEST_DATA_SOURCE_NAME is not set")
+	}
+
+	testStore(t, func(t *testing.T) storage.Store {
+		ctx := context.Background()
+
+		admin, err := database.NewDatabase(dataSourceName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(admin.Close)
+
+		schema := fmt.Sprintf("storage_test_%d", time.Now().UnixNano())
+		if _, err = admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if _, err := admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+				t.Error(err)
+			}
+		})
+
+		config, err := pgxpool.ParseConfig(dataSourceName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.ConnConfig.RuntimeParams["search_path"] = schema
+		pool, err := pgxpool.NewWithConfig(ctx, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		migrator, err := migrate.New(pool, migrations.FS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = migrator.Up(ctx, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		return storage.NewPostgres(pool)
+	})
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/apikeys/model"
+	"shortbin/pkg/response"
+)
+
+type APIKeysRepo struct {
+	db *sql.DB
+}
+
+func NewAPIKeysRepository(db *sql.DB) *APIKeysRepo {
+	return &APIKeysRepo{db: db}
+}
+
+func (r *APIKeysRepo) Create(ctx context.Context, key *model.APIKey) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.Create", "repository")
+	defer rootSpan.End()
+
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO api_keys (id, user_id, name, prefix, hashed_key, scopes, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	id, createdAt := uuid.NewString(), time.Now().UTC()
+	if _, err = r.db.ExecContext(ctx, query, id, key.UserID, key.Name, key.Prefix, key.HashedKey, string(scopes), createdAt); err != nil {
+		return uniqueViolation(err)
+	}
+
+	key.ID, key.CreatedAt = id, createdAt
+	return nil
+}
+
+func (r *APIKeysRepo) List(ctx context.Context, userID string) ([]*model.APIKey, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.List", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT id, user_id, name, prefix, scopes, created_at, last_used_at FROM api_keys WHERE user_id=$1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(rows *sql.Rows) (*model.APIKey, error) {
+		return scanAPIKey(rows)
+	})
+}
+
+func (r *APIKeysRepo) Delete(ctx context.Context, userID string, id string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.Delete", "repository")
+	defer rootSpan.End()
+
+	query := `DELETE FROM api_keys WHERE id=$1 AND user_id=$2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func (r *APIKeysRepo) GetByHash(ctx context.Context, hashedKey string) (*model.APIKey, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.GetByHash", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT id, user_id, name, prefix, scopes, created_at, last_used_at FROM api_keys WHERE hashed_key=$1`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, hashedKey))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(response.InvalidAPIKey)
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Touch records that the key was just used
+func (r *APIKeysRepo) Touch(ctx context.Context, id string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*APIKeysRepo.Touch", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE api_keys SET last_used_at=$1 WHERE id=$2`
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id)
+	return err
+}
+
+// scanAPIKey from a row of id, user_id, name, prefix, scopes, created_at and
+// last_used_at
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*model.APIKey, error) {
+	var key model.APIKey
+	var scopes string
+	if err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &scopes, &key.CreatedAt, &key.LastUsedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
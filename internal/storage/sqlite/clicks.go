@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"shortbin/internal/stats/model"
+)
+
+type ClicksRepo struct {
+	db *sql.DB
+}
+
+func NewClicksRepository(db *sql.DB) *ClicksRepo {
+	return &ClicksRepo{db: db}
+}
+
+// Record adds a click to every aggregate in one transaction. A click whose
+// event was recorded before is skipped, so redelivered events count once.
+func (r *ClicksRepo) Record(ctx context.Context, click *model.Click) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if click.EventID != "" {
+		result, err := tx.ExecContext(ctx, `INSERT INTO processed_click_events (event_id, processed_at) VALUES ($1, $2)
+			ON CONFLICT (event_id) DO NOTHING`, click.EventID, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+	}
+
+	statements := []struct {
+		query string
+		value any
+	}{
+		{`INSERT INTO link_clicks_daily (short_id, day, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, day) DO UPDATE SET clicks = link_clicks_daily.clicks + 1`,
+			click.ClickedAt.UTC().Truncate(24 * time.Hour)},
+		{`INSERT INTO link_referrers (short_id, referrer, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, referrer) DO UPDATE SET clicks = link_referrers.clicks + 1`,
+			click.Referrer},
+		{`INSERT INTO link_user_agents (short_id, user_agent, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, user_agent) DO UPDATE SET clicks = link_user_agents.clicks + 1`,
+			click.UserAgent},
+		{`INSERT INTO link_countries (short_id, country, clicks) VALUES ($1, $2, 1)
+			ON CONFLICT (short_id, country) DO UPDATE SET clicks = link_countries.clicks + 1`,
+			click.Country},
+	}
+	for _, statement := range statements {
+		if _, err = tx.ExecContext(ctx, statement.query, click.ShortID, statement.value); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// aggregateTables hold the statistics of a link, keyed by its short ID
+var aggregateTables = []string{"link_clicks_daily", "link_referrers", "link_user_agents", "link_countries"}
+
+// deleteStats removes in tx the statistics of the links selected by query, a
+// SELECT of short IDs, so that a short ID created again later does not
+// inherit the clicks of the old link. It runs before the links are deleted.
+func deleteStats(ctx context.Context, tx *sql.Tx, query string, args ...any) error {
+	for _, table := range aggregateTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE short_id IN (`+query+`)`, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/common/model"
+)
+
+type CreateRepo struct {
+	db *sql.DB
+}
+
+func NewCreateRepository(db *sql.DB) *CreateRepo {
+	return &CreateRepo{db: db}
+}
+
+func (r *CreateRepo) Create(ctx context.Context, url *model.URL) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*CreateRepo.Create", "repository")
+	defer rootSpan.End()
+
+	query := `INSERT INTO urls (short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query, url.ShortID, url.LongURL, url.UserID, url.RedirectType, url.HashedPassword, url.CreatedAt.UTC(), url.ExpiresAt.UTC())
+	return uniqueViolation(err)
+}
+
+// CreateBatch inserts urls in one transaction. Instead of failing, a url
+// whose short ID is taken is skipped and reported as false at its index.
+func (r *CreateRepo) CreateBatch(ctx context.Context, urls []*model.URL) ([]bool, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*CreateRepo.CreateBatch", "repository")
+	defer rootSpan.End()
+
+	query := `INSERT INTO urls (short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (short_id) DO NOTHING RETURNING short_id`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inserted := make([]bool, len(urls))
+	for i, url := range urls {
+		var shortID string
+		err = tx.QueryRowContext(ctx, query, url.ShortID, url.LongURL, url.UserID, url.RedirectType, url.HashedPassword, url.CreatedAt.UTC(), url.ExpiresAt.UTC()).Scan(&shortID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+		inserted[i] = true
+	}
+
+	return inserted, tx.Commit()
+}
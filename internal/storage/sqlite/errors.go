@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"shortbin/pkg/database"
+)
+
+// uniqueViolation wraps unique and primary key constraint errors in
+// database.ErrUniqueViolation, so that database.IsUniqueViolation matches
+// them like those of the other stores
+func uniqueViolation(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		if code := sqliteErr.Code(); code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return fmt.Errorf("%w: %w", database.ErrUniqueViolation, err)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/common/model"
+	"shortbin/internal/links/repository"
+	"shortbin/pkg/response"
+)
+
+type LinksRepo struct {
+	db *sql.DB
+}
+
+func NewLinksRepository(db *sql.DB) *LinksRepo {
+	return &LinksRepo{db: db}
+}
+
+func (r *LinksRepo) List(ctx context.Context, userID string, after *repository.Cursor, limit int) ([]*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.List", "repository")
+	defer rootSpan.End()
+
+	var rows *sql.Rows
+	var err error
+	if after == nil {
+		query := `SELECT short_id, long_url, user_id, redirect_type, created_at, expires_at FROM urls
+			WHERE user_id=$1 ORDER BY created_at DESC, short_id DESC LIMIT $2`
+		rows, err = r.db.QueryContext(ctx, query, userID, limit)
+	} else {
+		query := `SELECT short_id, long_url, user_id, redirect_type, created_at, expires_at FROM urls
+			WHERE user_id=$1 AND (created_at, short_id) < ($2, $3)
+			ORDER BY created_at DESC, short_id DESC LIMIT $4`
+		rows, err = r.db.QueryContext(ctx, query, userID, after.CreatedAt.UTC(), after.ShortID, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*model.URL
+	for rows.Next() {
+		var url model.URL
+		if err = rows.Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.RedirectType, &url.CreatedAt, &url.ExpiresAt); err != nil {
+			return nil, err
+		}
+		urls = append(urls, &url)
+	}
+
+	return urls, rows.Err()
+}
+
+func (r *LinksRepo) GetByID(ctx context.Context, userID string, shortID string) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.GetByID", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT short_id, long_url, user_id, redirect_type, created_at, expires_at FROM urls WHERE short_id=$1 AND user_id=$2`
+
+	var url model.URL
+	if err := r.db.QueryRowContext(ctx, query, shortID, userID).Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.RedirectType, &url.CreatedAt, &url.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(response.IDNotFound)
+		}
+		return nil, err
+	}
+
+	return &url, nil
+}
+
+func (r *LinksRepo) Update(ctx context.Context, url *model.URL) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.Update", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE urls SET long_url=$1, redirect_type=$2, expires_at=$3 WHERE short_id=$4 AND user_id=$5`
+	result, err := r.db.ExecContext(ctx, query, url.LongURL, url.RedirectType, url.ExpiresAt.UTC(), url.ShortID, url.UserID)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func (r *LinksRepo) Delete(ctx context.Context, userID string, shortID string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*LinksRepo.Delete", "repository")
+	defer rootSpan.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = deleteStats(ctx, tx, `SELECT short_id FROM urls WHERE short_id=$1 AND user_id=$2`, shortID, userID)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM urls WHERE short_id=$1 AND user_id=$2`
+	result, err := tx.ExecContext(ctx, query, shortID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// expectAffected returns IDNotFound when the statement matched no rows
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errors.New(response.IDNotFound)
+	}
+
+	return nil
+}
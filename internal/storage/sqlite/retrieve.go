@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/common/model"
+)
+
+type RetrieveRepo struct {
+	db *sql.DB
+}
+
+func NewRetrieveRepository(db *sql.DB) *RetrieveRepo {
+	return &RetrieveRepo{db: db}
+}
+
+func (r *RetrieveRepo) GetURLByID(ctx context.Context, id string) (*model.URL, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*RetrieveRepo.GetURLByID", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at FROM urls WHERE short_id=$1`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var url model.URL
+	if err := row.Scan(&url.ShortID, &url.LongURL, &url.UserID, &url.RedirectType, &url.HashedPassword, &url.CreatedAt, &url.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("id not found")
+		}
+		return nil, err
+	}
+
+	return &url, nil
+}
+
+// ListShortIDs returns up to limit short IDs ordered after the given one, for
+// seeding the Bloom filter
+func (r *RetrieveRepo) ListShortIDs(ctx context.Context, after string, limit int) ([]string, error) {
+	query := `SELECT short_id FROM urls WHERE short_id > $1 ORDER BY short_id LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(rows *sql.Rows) (string, error) {
+		var shortID string
+		err := rows.Scan(&shortID)
+		return shortID, err
+	})
+}
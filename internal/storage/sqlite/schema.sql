@@ -0,0 +1,111 @@
+-- SQLite counterpart of the Postgres migrations, applied on every start.
+-- IDs are generated by the application, timestamps are written in UTC.
+CREATE TABLE IF NOT EXISTS plans (
+    name            TEXT    PRIMARY KEY,
+    daily_quota     INTEGER NOT NULL DEFAULT 0,
+    monthly_quota   INTEGER NOT NULL DEFAULT 0,
+    max_expiry_days INTEGER NOT NULL DEFAULT 0,
+    custom_aliases  BOOLEAN NOT NULL DEFAULT 0,
+    password_links  BOOLEAN NOT NULL DEFAULT 0
+);
+
+INSERT OR IGNORE INTO plans (name, daily_quota, monthly_quota, max_expiry_days, custom_aliases, password_links) VALUES
+    ('free', 50, 500, 365, 0, 0),
+    ('pro', 1000, 20000, 1825, 1, 1),
+    ('enterprise', 0, 0, 0, 1, 1),
+    ('anonymous', 0, 0, 0, 1, 1);
+
+CREATE TABLE IF NOT EXISTS users (
+    id              TEXT      PRIMARY KEY,
+    email           TEXT      NOT NULL UNIQUE,
+    hashed_password TEXT      NOT NULL,
+    plan            TEXT      NOT NULL DEFAULT 'free' REFERENCES plans (name),
+    created_at      TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS urls (
+    short_id        TEXT      PRIMARY KEY,
+    long_url        TEXT      NOT NULL,
+    user_id         TEXT      REFERENCES users (id) ON DELETE CASCADE,
+    redirect_type   INTEGER   NOT NULL DEFAULT 301,
+    hashed_password TEXT,
+    created_at      TIMESTAMP NOT NULL,
+    expires_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS urls_user_id_created_at_idx ON urls (user_id, created_at, short_id);
+CREATE INDEX IF NOT EXISTS urls_expires_at_idx ON urls (expires_at);
+
+CREATE TABLE IF NOT EXISTS urls_archive (
+    id              INTEGER   PRIMARY KEY,
+    short_id        TEXT      NOT NULL,
+    long_url        TEXT      NOT NULL,
+    user_id         TEXT,
+    redirect_type   INTEGER   NOT NULL,
+    hashed_password TEXT,
+    created_at      TIMESTAMP NOT NULL,
+    expires_at      TIMESTAMP NOT NULL,
+    archived_at     TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS link_clicks_daily (
+    short_id TEXT    NOT NULL,
+    day      DATE    NOT NULL,
+    clicks   INTEGER NOT NULL,
+    PRIMARY KEY (short_id, day)
+);
+
+CREATE TABLE IF NOT EXISTS link_referrers (
+    short_id TEXT    NOT NULL,
+    referrer TEXT    NOT NULL,
+    clicks   INTEGER NOT NULL,
+    PRIMARY KEY (short_id, referrer)
+);
+
+CREATE TABLE IF NOT EXISTS link_user_agents (
+    short_id   TEXT    NOT NULL,
+    user_agent TEXT    NOT NULL,
+    clicks     INTEGER NOT NULL,
+    PRIMARY KEY (short_id, user_agent)
+);
+
+CREATE TABLE IF NOT EXISTS link_countries (
+    short_id TEXT    NOT NULL,
+    country  TEXT    NOT NULL,
+    clicks   INTEGER NOT NULL,
+    PRIMARY KEY (short_id, country)
+);
+
+CREATE TABLE IF NOT EXISTS processed_click_events (
+    event_id     TEXT      PRIMARY KEY,
+    processed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS processed_click_events_processed_at_idx ON processed_click_events (processed_at);
+
+CREATE TABLE IF NOT EXISTS link_usage_daily (
+    user_id TEXT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    day     DATE    NOT NULL,
+    created INTEGER NOT NULL,
+    PRIMARY KEY (user_id, day)
+);
+
+-- scopes is a JSON array
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           TEXT      PRIMARY KEY,
+    user_id      TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT      NOT NULL,
+    prefix       TEXT      NOT NULL,
+    hashed_key   TEXT      NOT NULL UNIQUE,
+    scopes       TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS blocked_domains (
+    domain     TEXT      PRIMARY KEY,
+    reason     TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+
+	_ "modernc.org/sqlite" // registers the pure Go "sqlite" driver
+
+	apiKeysRepository "shortbin/internal/apikeys/repository"
+	authRepository "shortbin/internal/auth/repository"
+	createRepository "shortbin/internal/create/repository"
+	linksRepository "shortbin/internal/links/repository"
+	plansRepository "shortbin/internal/plans/repository"
+	retrieveRepository "shortbin/internal/retrieve/repository"
+	statsRepository "shortbin/internal/stats/repository"
+	sweeperRepository "shortbin/internal/sweeper/repository"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Store keeps everything in a single SQLite database file, for running
+// shortbin on one node without Postgres
+type Store struct {
+	db *sql.DB
+}
+
+// Open the database at path, creating it and its tables when missing
+func Open(ctx context.Context, path string) (*Store, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer, serialising in the pool instead of
+	// retrying on SQLITE_BUSY. This also keeps :memory: databases to one
+	// connection, as each connection would get a database of its own.
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating sqlite schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Create() createRepository.ICreateRepository {
+	return NewCreateRepository(s.db)
+}
+
+func (s *Store) Retrieve() retrieveRepository.IRetrieveRepository {
+	return NewRetrieveRepository(s.db)
+}
+
+func (s *Store) Users() authRepository.IUserRepository {
+	return NewUserRepository(s.db)
+}
+
+func (s *Store) Links() linksRepository.ILinksRepository {
+	return NewLinksRepository(s.db)
+}
+
+func (s *Store) Stats() statsRepository.IStatsRepository {
+	return NewStatsRepository(s.db)
+}
+
+func (s *Store) Clicks() statsRepository.IClicksRepository {
+	return NewClicksRepository(s.db)
+}
+
+func (s *Store) Plans() plansRepository.IPlansRepository {
+	return NewPlansRepository(s.db)
+}
+
+func (s *Store) APIKeys() apiKeysRepository.IAPIKeysRepository {
+	return NewAPIKeysRepository(s.db)
+}
+
+func (s *Store) Sweeper() sweeperRepository.ISweeperRepository {
+	return NewSweeperRepository(s.db)
+}
+
+func (s *Store) BlockedDomains(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT domain FROM blocked_domains`)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(rows *sql.Rows) (string, error) {
+		var domain string
+		err := rows.Scan(&domain)
+		return domain, err
+	})
+}
+
+func (s *Store) Close() {
+	s.db.Close()
+}
+
+// collect scans every row with scan and closes rows, like pgx.CollectRows
+func collect[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	values := []T{}
+	for rows.Next() {
+		value, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SweeperRepo struct {
+	db *sql.DB
+}
+
+func NewSweeperRepository(db *sql.DB) *SweeperRepo {
+	return &SweeperRepo{db: db}
+}
+
+// expiredBatch selects the short IDs of a batch of expired links, the
+// statements of a sweep see the same batch as there is a single writer
+const expiredBatch = `SELECT short_id FROM urls WHERE expires_at < $1 ORDER BY short_id LIMIT $2`
+
+func (r *SweeperRepo) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	return sweep(ctx, tx, before, limit)
+}
+
+// ArchiveExpired copies a batch of expired links to urls_archive and deletes
+// them. SQLite has no DELETE in WITH, so this takes several statements.
+func (r *SweeperRepo) ArchiveExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO urls_archive (short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at, archived_at)
+		SELECT short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at, $3 FROM urls
+		WHERE expires_at < $1 ORDER BY short_id LIMIT $2`
+	if _, err = tx.ExecContext(ctx, query, before.UTC(), limit, time.Now().UTC()); err != nil {
+		return 0, err
+	}
+
+	return sweep(ctx, tx, before, limit)
+}
+
+// sweep deletes a batch of expired links and their statistics, and commits tx
+func sweep(ctx context.Context, tx *sql.Tx, before time.Time, limit int) (int64, error) {
+	if err := deleteStats(ctx, tx, expiredBatch, before.UTC(), limit); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM urls WHERE short_id IN (`+expiredBatch+`)`, before.UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	swept, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return swept, tx.Commit()
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.elastic.co/apm/v2"
+
+	"shortbin/internal/auth/model"
+)
+
+type UserRepo struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) Create(ctx context.Context, email string, hashedPassword string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.Create", "repository")
+	defer rootSpan.End()
+
+	query := `INSERT INTO users (id, email, hashed_password, created_at) VALUES ($1, $2, $3, $4)`
+
+	createdUser := model.User{
+		ID:             uuid.NewString(),
+		Email:          email,
+		HashedPassword: hashedPassword,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if _, err := r.db.ExecContext(ctx, query, createdUser.ID, createdUser.Email, createdUser.HashedPassword, createdUser.CreatedAt); err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	return &createdUser, nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, user *model.User) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.Update", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE users SET email=$1, hashed_password=$2 WHERE id=$3`
+	_, err := r.db.ExecContext(ctx, query, user.Email, user.HashedPassword, user.ID)
+	return uniqueViolation(err)
+}
+
+func (r *UserRepo) UpdatePassword(ctx context.Context, userID string, hashedPassword string) error {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.UpdatePassword", "repository")
+	defer rootSpan.End()
+
+	query := `UPDATE users SET hashed_password=$1 WHERE id=$2`
+	_, err := r.db.ExecContext(ctx, query, hashedPassword, userID)
+	return err
+}
+
+func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.GetUserByID", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT id, created_at, email, hashed_password FROM users WHERE id=$1`
+
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.CreatedAt, &user.Email, &user.HashedPassword); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	rootSpan, ctx := apm.StartSpan(ctx, "*UserRepo.GetUserByEmail", "repository")
+	defer rootSpan.End()
+
+	query := `SELECT id, created_at, email, hashed_password FROM users WHERE email=$1`
+
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.CreatedAt, &user.Email, &user.HashedPassword); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiKeysRepository "shortbin/internal/apikeys/repository"
+	authRepository "shortbin/internal/auth/repository"
+	createRepository "shortbin/internal/create/repository"
+	linksRepository "shortbin/internal/links/repository"
+	plansRepository "shortbin/internal/plans/repository"
+	retrieveRepository "shortbin/internal/retrieve/repository"
+	statsRepository "shortbin/internal/stats/repository"
+	"shortbin/internal/storage/memory"
+	"shortbin/internal/storage/sqlite"
+	sweeperRepository "shortbin/internal/sweeper/repository"
+	"shortbin/pkg/database"
+)
+
+// Store is a storage backend, providing the repositories of every module
+type Store interface {
+	Create() createRepository.ICreateRepository
+	Retrieve() retrieveRepository.IRetrieveRepository
+	Users() authRepository.IUserRepository
+	Links() linksRepository.ILinksRepository
+	Stats() statsRepository.IStatsRepository
+	Clicks() statsRepository.IClicksRepository
+	Plans() plansRepository.IPlansRepository
+	APIKeys() apiKeysRepository.IAPIKeysRepository
+	Sweeper() sweeperRepository.ISweeperRepository
+	BlockedDomains(ctx context.Context) ([]string, error)
+	Close()
+}
+
+// Open the store for dataSourceName, the backend is chosen by its scheme:
+//
+//	sqlite://<path>  SQLite database file, created when missing
+//	memory://        in-memory store, lost on exit
+//
+// anything else is handed to Postgres
+func Open(ctx context.Context, dataSourceName string) (Store, error) {
+	scheme, rest, _ := strings.Cut(dataSourceName, "://")
+	switch scheme {
+	case "sqlite":
+		if rest == "" {
+			return nil, fmt.Errorf("sqlite data source has no path")
+		}
+		return sqlite.Open(ctx, rest)
+	case "memory":
+		return memory.New(), nil
+	default:
+		pool, err := database.NewDatabase(dataSourceName)
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgres(pool), nil
+	}
+}
@@ -0,0 +1,27 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"shortbin/internal/storage"
+	"shortbin/internal/storage/memory"
+	"shortbin/internal/storage/sqlite"
+)
+
+func TestMemory(t *testing.T) {
+	testStore(t, func(t *testing.T) storage.Store {
+		return memory.New()
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	testStore(t, func(t *testing.T) storage.Store {
+		store, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "shortbin.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	})
+}
@@ -0,0 +1,179 @@
+package storage_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	apiKeysModel "shortbin/internal/apikeys/model"
+	authModel "shortbin/internal/auth/model"
+	"shortbin/internal/common/model"
+	linksRepository "shortbin/internal/links/repository"
+	"shortbin/internal/storage"
+	"shortbin/pkg/database"
+)
+
+// testStore runs the repository suite against one backend, open returns an
+// empty store for every test
+func testStore(t *testing.T, open func(t *testing.T) storage.Store) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store storage.Store)
+	}{
+		{"UniqueViolation", testUniqueViolation},
+		{"ListPagination", testListPagination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := open(t)
+			t.Cleanup(store.Close)
+			tt.run(t, store)
+		})
+	}
+}
+
+// testUniqueViolation checks that every backend reports a taken unique value
+// as database.IsUniqueViolation, which the services rely on
+func testUniqueViolation(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+	user := createUser(t, store, "owner@example.com")
+	other := createUser(t, store, "other@example.com")
+
+	// insert is expected to succeed once and then conflict, unless taken,
+	// when it conflicts with a row of the setup right away
+	tests := []struct {
+		name   string
+		taken  bool
+		insert func() error
+	}{
+		{"short ID", false, func() error {
+			return store.Create().Create(ctx, newURL(user.ID, "taken01", time.Now()))
+		}},
+		{"email", false, func() error {
+			_, err := store.Users().Create(ctx, "taken@example.com", "hashed")
+			return err
+		}},
+		{"email on update", true, func() error {
+			return store.Users().Update(ctx, &authModel.User{ID: other.ID, Email: user.Email, HashedPassword: "hashed"})
+		}},
+		{"API key", false, func() error {
+			return store.APIKeys().Create(ctx, &apiKeysModel.APIKey{
+				UserID:    user.ID,
+				Name:      "ci",
+				Prefix:    "sb_taken",
+				HashedKey: "taken",
+				Scopes:    []string{"create"},
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.taken {
+				if err := tt.insert(); err != nil {
+					t.Fatalf("first insert: %v", err)
+				}
+			}
+
+			err := tt.insert()
+			if !database.IsUniqueViolation(err) {
+				t.Errorf("second insert: got %v, want a unique violation", err)
+			}
+		})
+	}
+}
+
+// testListPagination checks that pages follow (created_at, short_id)
+// descending, links created in the same instant included, without skipping
+// or repeating a link whatever the page size
+func testListPagination(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+	user := createUser(t, store, "owner@example.com")
+	other := createUser(t, store, "other@example.com")
+
+	now := time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)
+	links := []struct {
+		shortID   string
+		createdAt time.Time
+	}{
+		{"page003", now.Add(-time.Minute)},
+		{"page001", now},
+		{"page005", now.Add(-2 * time.Minute)},
+		{"page002", now},
+		{"page004", now.Add(-time.Minute)},
+	}
+	for _, link := range links {
+		if err := store.Create().Create(ctx, newURL(user.ID, link.shortID, link.createdAt)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Create().Create(ctx, newURL(other.ID, "other01", now)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"page002", "page001", "page004", "page003", "page005"}
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{"one per page", 1},
+		{"two per page", 2},
+		{"three per page", 3},
+		{"exact page", 5},
+		{"single page", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var after *linksRepository.Cursor
+			for page := 0; page <= len(want); page++ {
+				urls, err := store.Links().List(ctx, user.ID, after, tt.limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(urls) > tt.limit {
+					t.Fatalf("page %d has %d links, limit is %d", page, len(urls), tt.limit)
+				}
+				if len(urls) == 0 {
+					break
+				}
+
+				for _, url := range urls {
+					got = append(got, url.ShortID)
+				}
+				last := urls[len(urls)-1]
+				after = &linksRepository.Cursor{CreatedAt: last.CreatedAt, ShortID: last.ShortID}
+			}
+
+			if !slices.Equal(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func createUser(t *testing.T, store storage.Store, email string) *authModel.User {
+	t.Helper()
+
+	user, err := store.Users().Create(context.Background(), email, "hashed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user
+}
+
+func newURL(userID string, shortID string, createdAt time.Time) *model.URL {
+	return &model.URL{
+		ShortID:      shortID,
+		LongURL:      "https://golang.org/doc/",
+		UserID:       &userID,
+		RedirectType: 301,
+		CreatedAt:    createdAt,
+		ExpiresAt:    createdAt.Add(24 * time.Hour),
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	statsRepository "shortbin/internal/stats/repository"
+)
+
+type ISweeperRepository interface {
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+	ArchiveExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type SweeperRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSweeperRepository(db *pgxpool.Pool) *SweeperRepo {
+	return &SweeperRepo{db: db}
+}
+
+func (r *SweeperRepo) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `DELETE FROM urls WHERE short_id IN (
+		SELECT short_id FROM urls WHERE expires_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED
+	) RETURNING short_id`
+
+	return r.sweep(ctx, query, before, limit)
+}
+
+func (r *SweeperRepo) ArchiveExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `WITH expired AS (
+		DELETE FROM urls WHERE short_id IN (
+			SELECT short_id FROM urls WHERE expires_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED
+		) RETURNING short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at
+	)
+	INSERT INTO urls_archive (short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at)
+	SELECT short_id, long_url, user_id, redirect_type, hashed_password, created_at, expires_at FROM expired
+	RETURNING short_id`
+
+	return r.sweep(ctx, query, before, limit)
+}
+
+// sweep runs query, which removes a batch of expired links and returns their
+// short IDs, and deletes the statistics of those links in the same transaction
+func (r *SweeperRepo) sweep(ctx context.Context, query string, before time.Time, limit int) (int64, error) {
+	var swept int64
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, before, limit)
+		if err != nil {
+			return err
+		}
+
+		shortIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+
+		if err = statsRepository.DeleteStats(ctx, tx, shortIDs); err != nil {
+			return err
+		}
+
+		swept = int64(len(shortIDs))
+		return nil
+	})
+
+	return swept, err
+}
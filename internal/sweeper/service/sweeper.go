@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"shortbin/internal/sweeper/repository"
+	"shortbin/pkg/config"
+	"shortbin/pkg/logger"
+)
+
+const (
+	defaultBatchSize = 1000
+	defaultInterval  = 60 // minutes
+)
+
+// Sweeper periodically removes expired links from urls, archiving them into
+// urls_archive when configured to do so
+type Sweeper struct {
+	repo repository.ISweeperRepository
+	cfg  config.Sweeper
+}
+
+func NewSweeper(repo repository.ISweeperRepository, cfg config.Sweeper) *Sweeper {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	return &Sweeper{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Run sweeps once immediately and then every interval until ctx is done
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		s.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep removes expired links in batches until none are left
+func (s *Sweeper) Sweep(ctx context.Context) {
+	now := time.Now()
+
+	var total int64
+	for ctx.Err() == nil {
+		var n int64
+		var err error
+		if s.cfg.Archive {
+			n, err = s.repo.ArchiveExpired(ctx, now, s.cfg.BatchSize)
+		} else {
+			n, err = s.repo.DeleteExpired(ctx, now, s.cfg.BatchSize)
+		}
+		if err != nil {
+			logger.Errorf("failed to sweep expired links: %v", err)
+			return
+		}
+
+		total += n
+		if n < int64(s.cfg.BatchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Infof("swept %d expired links", total)
+	}
+}
@@ -0,0 +1,16 @@
+-- IF NOT EXISTS so that databases created before migrations existed can be
+-- brought under migration
+CREATE TABLE IF NOT EXISTS users (
+    id              uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    email           text        NOT NULL UNIQUE,
+    hashed_password text        NOT NULL,
+    created_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS urls (
+    short_id   text        PRIMARY KEY,
+    long_url   text        NOT NULL,
+    user_id    uuid        REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL
+);
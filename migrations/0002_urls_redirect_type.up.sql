@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS redirect_type integer NOT NULL DEFAULT 301;
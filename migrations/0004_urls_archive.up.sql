@@ -0,0 +1,15 @@
+-- expired links moved aside by the sweeper, a short ID can be archived more
+-- than once as it becomes free again after expiring
+CREATE TABLE urls_archive (
+    id              bigserial   PRIMARY KEY,
+    short_id        text        NOT NULL,
+    long_url        text        NOT NULL,
+    user_id         uuid,
+    redirect_type   integer     NOT NULL,
+    hashed_password text,
+    created_at      timestamptz NOT NULL,
+    expires_at      timestamptz NOT NULL,
+    archived_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX urls_archive_short_id_idx ON urls_archive (short_id);
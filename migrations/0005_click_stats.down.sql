@@ -0,0 +1,4 @@
+DROP TABLE link_countries;
+DROP TABLE link_user_agents;
+DROP TABLE link_referrers;
+DROP TABLE link_clicks_daily;
@@ -0,0 +1,27 @@
+CREATE TABLE link_clicks_daily (
+    short_id text   NOT NULL,
+    day      date   NOT NULL,
+    clicks   bigint NOT NULL,
+    PRIMARY KEY (short_id, day)
+);
+
+CREATE TABLE link_referrers (
+    short_id text   NOT NULL,
+    referrer text   NOT NULL,
+    clicks   bigint NOT NULL,
+    PRIMARY KEY (short_id, referrer)
+);
+
+CREATE TABLE link_user_agents (
+    short_id   text   NOT NULL,
+    user_agent text   NOT NULL,
+    clicks     bigint NOT NULL,
+    PRIMARY KEY (short_id, user_agent)
+);
+
+CREATE TABLE link_countries (
+    short_id text   NOT NULL,
+    country  text   NOT NULL,
+    clicks   bigint NOT NULL,
+    PRIMARY KEY (short_id, country)
+);
@@ -0,0 +1 @@
+DROP TABLE click_outbox;
@@ -0,0 +1,8 @@
+CREATE TABLE click_outbox (
+    id         bigserial   PRIMARY KEY,
+    topic      text        NOT NULL,
+    key        text        NOT NULL,
+    value      bytea       NOT NULL,
+    headers    jsonb,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,11 @@
+-- written by the postgres event sink, for deployments without Kafka
+CREATE TABLE click_events (
+    id         bigserial   PRIMARY KEY,
+    topic      text        NOT NULL,
+    key        text        NOT NULL,
+    value      bytea       NOT NULL,
+    headers    jsonb,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX click_events_created_at_idx ON click_events (created_at);
@@ -0,0 +1,3 @@
+DROP TABLE link_usage_daily;
+ALTER TABLE users DROP COLUMN plan;
+DROP TABLE plans;
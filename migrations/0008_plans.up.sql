@@ -0,0 +1,23 @@
+-- zero quotas and max_expiry_days mean unlimited
+CREATE TABLE plans (
+    name            text    PRIMARY KEY,
+    daily_quota     integer NOT NULL DEFAULT 0,
+    monthly_quota   integer NOT NULL DEFAULT 0,
+    max_expiry_days integer NOT NULL DEFAULT 0,
+    custom_aliases  boolean NOT NULL DEFAULT false,
+    password_links  boolean NOT NULL DEFAULT false
+);
+
+INSERT INTO plans (name, daily_quota, monthly_quota, max_expiry_days, custom_aliases, password_links) VALUES
+    ('free', 50, 500, 365, false, false),
+    ('pro', 1000, 20000, 1825, true, true),
+    ('enterprise', 0, 0, 0, true, true);
+
+ALTER TABLE users ADD COLUMN plan text NOT NULL DEFAULT 'free' REFERENCES plans (name);
+
+CREATE TABLE link_usage_daily (
+    user_id uuid    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    day     date    NOT NULL,
+    created integer NOT NULL,
+    PRIMARY KEY (user_id, day)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE api_keys (
+    id           uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         text        NOT NULL,
+    prefix       text        NOT NULL,
+    hashed_key   text        NOT NULL UNIQUE,
+    scopes       text[]      NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    last_used_at timestamptz
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id, created_at);
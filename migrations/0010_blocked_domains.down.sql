@@ -0,0 +1 @@
+DROP TABLE blocked_domains;
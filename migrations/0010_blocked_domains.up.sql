@@ -0,0 +1,7 @@
+-- maintained by admins, blocking a domain also blocks its subdomains.
+-- Domains are stored lower case in punycode form.
+CREATE TABLE blocked_domains (
+    domain     text        PRIMARY KEY,
+    reason     text,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
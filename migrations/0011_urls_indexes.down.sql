@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS urls_expires_at_idx;
+DROP INDEX IF EXISTS urls_user_id_created_at_idx;
@@ -0,0 +1,5 @@
+-- listing a user's links, newest first
+CREATE INDEX IF NOT EXISTS urls_user_id_created_at_idx ON urls (user_id, created_at, short_id);
+
+-- finding expired links for the sweeper
+CREATE INDEX IF NOT EXISTS urls_expires_at_idx ON urls (expires_at);
@@ -0,0 +1,9 @@
+-- event IDs of the clicks already added to the statistics, so that events
+-- redelivered by Kafka are only counted once. Rows older than the retention
+-- of the clicks topics can be deleted.
+CREATE TABLE processed_click_events (
+    event_id     text        PRIMARY KEY,
+    processed_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX processed_click_events_processed_at_idx ON processed_click_events (processed_at);
@@ -0,0 +1 @@
+DELETE FROM plans WHERE name = 'anonymous';
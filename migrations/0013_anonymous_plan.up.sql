@@ -0,0 +1,5 @@
+-- applies to links created without logging in, with the features they had
+-- before plans were introduced. Restrict it to change what anonymous users
+-- can do.
+INSERT INTO plans (name, daily_quota, monthly_quota, max_expiry_days, custom_aliases, password_links) VALUES
+    ('anonymous', 0, 0, 0, true, true);
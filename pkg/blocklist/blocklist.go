@@ -0,0 +1,89 @@
+package blocklist
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"shortbin/pkg/logger"
+)
+
+const defaultRefreshInterval = time.Minute
+
+// ListFunc returns every blocked domain
+type ListFunc func(ctx context.Context) ([]string, error)
+
+// Blocklist of domains links may not point to, kept in the blocked_domains
+// table by admins and cached in memory. Blocking a domain also blocks all of
+// its subdomains.
+type Blocklist struct {
+	list    ListFunc
+	domains atomic.Pointer[map[string]struct{}]
+}
+
+// New Blocklist of the domains returned by list, it is empty until loaded
+func New(list ListFunc) *Blocklist {
+	b := &Blocklist{list: list}
+	b.domains.Store(&map[string]struct{}{})
+	return b
+}
+
+// Load the blocked domains
+func (b *Blocklist) Load(ctx context.Context) error {
+	list, err := b.list(ctx)
+	if err != nil {
+		return err
+	}
+
+	domains := make(map[string]struct{}, len(list))
+	for _, domain := range list {
+		domains[strings.ToLower(strings.TrimSuffix(domain, "."))] = struct{}{}
+	}
+	b.domains.Store(&domains)
+
+	return nil
+}
+
+// Run reloads the blocked domains every interval until ctx is done, so that
+// changes made by admins are picked up without a restart
+func (b *Blocklist) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Load(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("Failed to reload domain blocklist ", err)
+			}
+		}
+	}
+}
+
+// IsBlocked reports whether host or any of its parent domains is blocked.
+// host is expected in its normalized, lower case punycode form.
+func (b *Blocklist) IsBlocked(host string) bool {
+	domains := *b.domains.Load()
+	if len(domains) == 0 {
+		return false
+	}
+
+	for {
+		if _, ok := domains[host]; ok {
+			return true
+		}
+
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			return false
+		}
+		host = parent
+	}
+}
@@ -0,0 +1,201 @@
+package bloom
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"shortbin/pkg/logger"
+	"shortbin/pkg/redis"
+)
+
+const (
+	defaultKey    = "bloom:short_ids"
+	defaultSize   = 1 << 27 // 16MiB bitmap, ~1% false positives at 14M IDs
+	defaultHashes = 7
+
+	seedBatchSize  = 10000
+	reseedInterval = time.Minute
+)
+
+// Filter answers whether a short ID may exist. A false answer is definite,
+// so lookups for unknown IDs can be rejected without touching the database.
+type Filter interface {
+	Add(ids ...string) error
+	MightContain(id string) (bool, error)
+}
+
+// Config bloom
+type Config struct {
+	Key    string
+	Size   uint64
+	Hashes int
+}
+
+// ListFunc returns up to limit short IDs ordered after the given one
+type ListFunc func(ctx context.Context, after string, limit int) ([]string, error)
+
+// RedisFilter is a Bloom filter kept in a Redis bitmap so that it is shared
+// between instances. Until it has been seeded with the existing IDs it
+// answers true for every ID.
+//
+// Being seeded is marked by a bit past the end of the filter, in the bitmap
+// itself, so that a bitmap evicted or deleted in Redis takes the marker with
+// it rather than leaving a seeded but empty filter. Lookups read the marker
+// along with the ID's bits and start a re-seed when it is gone.
+type RedisFilter struct {
+	redis  redis.IRedis
+	key    string
+	size   uint64
+	hashes int
+	ready  atomic.Bool
+	// lost is signalled when a lookup finds the seeded marker gone
+	lost chan struct{}
+}
+
+// New Redis backed Filter
+func New(redis redis.IRedis, cfg Config) *RedisFilter {
+	if cfg.Key == "" {
+		cfg.Key = defaultKey
+	}
+	if cfg.Size == 0 {
+		cfg.Size = defaultSize
+	}
+	if cfg.Hashes <= 0 {
+		cfg.Hashes = defaultHashes
+	}
+
+	return &RedisFilter{
+		redis:  redis,
+		key:    cfg.Key,
+		size:   cfg.Size,
+		hashes: cfg.Hashes,
+		lost:   make(chan struct{}, 1),
+	}
+}
+
+func (f *RedisFilter) Add(ids ...string) error {
+	offsets := make([]uint64, 0, len(ids)*f.hashes)
+	for _, id := range ids {
+		offsets = append(offsets, f.offsets(id)...)
+	}
+
+	return f.redis.SetBits(f.key, offsets)
+}
+
+func (f *RedisFilter) MightContain(id string) (bool, error) {
+	if !f.ready.Load() {
+		return true, nil
+	}
+
+	bits, err := f.redis.GetBits(f.key, append(f.offsets(id), f.size))
+	if err != nil {
+		return true, err
+	}
+
+	if !bits[f.hashes] {
+		// the bitmap was evicted or deleted, answer true until re-seeded
+		f.ready.Store(false)
+		select {
+		case f.lost <- struct{}{}:
+		default:
+		}
+		return true, nil
+	}
+
+	for _, bit := range bits[:f.hashes] {
+		if !bit {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Run seeds the filter, and seeds it again whenever its bitmap goes missing,
+// until ctx is done. Seeding is a single bit read once the bitmap is seeded,
+// so the marker is also checked every reseedInterval without any lookups.
+func (f *RedisFilter) Run(ctx context.Context, list ListFunc) {
+	for {
+		if err := f.Seed(ctx, list); err != nil {
+			logger.Error("Failed to seed bloom filter ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.lost:
+		case <-time.After(reseedInterval):
+		}
+	}
+}
+
+// Seed adds every existing short ID to the filter unless another instance
+// already has, and only then starts answering false. IDs created while
+// seeding are added by the create path, so none are missed.
+func (f *RedisFilter) Seed(ctx context.Context, list ListFunc) error {
+	marker, err := f.redis.GetBits(f.key, []uint64{f.size})
+	if err != nil {
+		return err
+	}
+
+	if !marker[0] {
+		var after string
+		var total int
+		for {
+			ids, err := list(ctx, after, seedBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				break
+			}
+
+			if err = f.Add(ids...); err != nil {
+				return err
+			}
+
+			total += len(ids)
+			after = ids[len(ids)-1]
+		}
+
+		if err = f.redis.SetBits(f.key, []uint64{f.size}); err != nil {
+			return err
+		}
+		logger.Infof("bloom filter seeded with %d short ids", total)
+	}
+
+	f.ready.Store(true)
+	return nil
+}
+
+// offsets of id's bits using double hashing over the two halves of FNV-1a
+func (f *RedisFilter) offsets(id string) []uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	sum := h.Sum64()
+	h1, h2 := sum&0xffffffff, sum>>32|1
+
+	offsets := make([]uint64, f.hashes)
+	for i := range offsets {
+		offsets[i] = (h1 + uint64(i)*h2) % f.size
+	}
+
+	return offsets
+}
+
+type disabled struct{}
+
+// Disabled Filter that may contain every ID, used when the filter is off
+func Disabled() Filter {
+	return disabled{}
+}
+
+func (disabled) Add(...string) error {
+	return nil
+}
+
+func (disabled) MightContain(string) (bool, error) {
+	return true, nil
+}
@@ -12,25 +12,72 @@ const ProductionEnv = "production"
 type Config struct {
 	Environment       string       `mapstructure:"environment"`
 	HTTPPort          int          `mapstructure:"http_port"`
+	HTTPTimeouts      HTTPTimeouts `mapstructure:"http_timeouts"`
+	GRPCPort          int          `mapstructure:"grpc_port"` // 0 disables the gRPC API
 	AuthSecret        string       `mapstructure:"auth_secret"`
-	DataSourceName    string       `mapstructure:"data_source_name"`
+	DataSourceName    string       `mapstructure:"data_source_name"` // postgres, sqlite://<path> or memory://
+	MigrateOnStart    bool         `mapstructure:"migrate_on_start"`
 	ShortIDLength     ShortIDLimit `mapstructure:"short_id_length"`
+	IDGenerator       IDGenerator  `mapstructure:"id_generator"`
 	ExpirationInYears int          `mapstructure:"expiration_in_years"`
+	RedirectType      int          `mapstructure:"redirect_type"`
 	Kafka             Kafka        `mapstructure:"kafka"`
 	Redis             Redis        `mapstructure:"redis"`
+	BloomFilter       BloomFilter  `mapstructure:"bloom_filter"`
+	RateLimit         RateLimit    `mapstructure:"rate_limit"`
+	Outbox            Outbox       `mapstructure:"outbox"`
+	EventSink         EventSink    `mapstructure:"event_sink"`
+	Sweeper           Sweeper      `mapstructure:"sweeper"`
+	LinkPassword      LinkPassword `mapstructure:"link_password"`
+	CountryHeader     string       `mapstructure:"country_header"`
+	BulkCreateMax     int          `mapstructure:"bulk_create_max"`
+	LongURL           LongURL      `mapstructure:"long_url"`
 	EnablePprof       bool         `mapstructure:"enable_pprof"`
 }
 
+// HTTPTimeouts in seconds
+type HTTPTimeouts struct {
+	Read     time.Duration `mapstructure:"read"`
+	Write    time.Duration `mapstructure:"write"`
+	Idle     time.Duration `mapstructure:"idle"`
+	Shutdown time.Duration `mapstructure:"shutdown"`
+}
+
 type ShortIDLimit struct {
 	Default int `mapstructure:"default"`
 	Min     int `mapstructure:"min"`
 	Max     int `mapstructure:"max"`
 }
 
+type IDGenerator struct {
+	Strategy   string `mapstructure:"strategy"`
+	NodeID     int64  `mapstructure:"node_id"`
+	MaxRetries int    `mapstructure:"max_retries"`
+}
+
 type Kafka struct {
 	Broker            string `mapstructure:"broker"`
 	ClicksTopic       string `mapstructure:"clicks_topic"`
 	PublicClicksTopic string `mapstructure:"public_clicks_topic"`
+	ConsumerGroup     string `mapstructure:"consumer_group"`
+	Encoding          string `mapstructure:"encoding"`
+	QueueSize         int    `mapstructure:"queue_size"`
+	BatchSize         int    `mapstructure:"batch_size"`
+	Linger            int    `mapstructure:"linger"` // milliseconds
+	Policy            string `mapstructure:"policy"`
+}
+
+// EventSink where click events are written: kafka (default), postgres,
+// file, stdout or noop
+type EventSink struct {
+	Type     string `mapstructure:"type"`
+	FilePath string `mapstructure:"file_path"`
+}
+
+type Outbox struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	PollInterval time.Duration `mapstructure:"poll_interval"` // seconds
 }
 
 type Redis struct {
@@ -38,6 +85,74 @@ type Redis struct {
 	Password string        `mapstructure:"password"`
 	Database int           `mapstructure:"database"`
 	TTL      time.Duration `mapstructure:"ttl"`
+	// NegativeTTL in seconds for caching unknown short IDs
+	NegativeTTL time.Duration `mapstructure:"negative_ttl"`
+	Local       LocalCache    `mapstructure:"local"`
+	// EarlyRefresh re-reads hot entries shortly before they are due,
+	// see https://cseweb.ucsd.edu/~avattani/papers/cache_stampede.pdf
+	EarlyRefresh EarlyRefresh `mapstructure:"early_refresh"`
+}
+
+// EarlyRefresh, a Beta above 1 favours refreshing earlier, defaults to 1
+type EarlyRefresh struct {
+	Enabled bool    `mapstructure:"enabled"`
+	Beta    float64 `mapstructure:"beta"`
+}
+
+// LocalCache is the in-process tier in front of Redis, TTL is in seconds
+type LocalCache struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Size    int           `mapstructure:"size"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	Channel string        `mapstructure:"channel"`
+}
+
+// BloomFilter of existing short IDs, kept in Redis. Size is in bits.
+type BloomFilter struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Key     string `mapstructure:"key"`
+	Size    uint64 `mapstructure:"size"`
+	Hashes  int    `mapstructure:"hashes"`
+}
+
+// RateLimit policies by name: create, auth and redirect
+type RateLimit struct {
+	Enabled    bool                       `mapstructure:"enabled"`
+	MemorySize int                        `mapstructure:"memory_size"`
+	Policies   map[string]RateLimitPolicy `mapstructure:"policies"`
+}
+
+// RateLimitPolicy allows Limit requests per Period seconds, Burst defaults to
+// Limit. KeyBy is ip (default), user or api_key.
+type RateLimitPolicy struct {
+	Limit  int           `mapstructure:"limit"`
+	Period time.Duration `mapstructure:"period"`
+	Burst  int           `mapstructure:"burst"`
+	KeyBy  string        `mapstructure:"key_by"`
+}
+
+// LongURL policy for the URLs links point to. HostPolicy is none (default),
+// resolve or public. OwnHosts are the hosts shortbin is served on, links to
+// them would redirect in a loop. The host a request was served on is always
+// one of them. BlocklistRefresh is in seconds.
+type LongURL struct {
+	MaxLength        int           `mapstructure:"max_length"`
+	Schemes          []string      `mapstructure:"schemes"`
+	HostPolicy       string        `mapstructure:"host_policy"`
+	OwnHosts         []string      `mapstructure:"own_hosts"`
+	BlocklistRefresh time.Duration `mapstructure:"blocklist_refresh"`
+}
+
+type Sweeper struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batch_size"`
+	Archive   bool          `mapstructure:"archive"`
+}
+
+type LinkPassword struct {
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	AttemptWindow time.Duration `mapstructure:"attempt_window"`
 }
 
 var cfg Config
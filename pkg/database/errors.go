@@ -0,0 +1,30 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrUniqueViolation is returned, or wrapped, by stores other than Postgres
+// for unique constraint violations
+var ErrUniqueViolation = errors.New("unique violation")
+
+// IsUniqueViolation reports whether err is a unique constraint violation of
+// any storage backend
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique violation code
+	}
+
+	return errors.Is(err, ErrUniqueViolation)
+}
+
+// IsNotFound reports whether err means no row matched, pgx and database/sql
+// have their own errors for it
+func IsNotFound(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows)
+}
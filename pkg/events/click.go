@@ -0,0 +1,64 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// ClickEventSchemaVersion is bumped on every incompatible change to ClickEvent.
+// Adding fields is compatible and does not require a bump.
+const ClickEventSchemaVersion = 1
+
+// ClickEvent is written to the clicks topics on every redirect. The JSON keys
+// of the payload fields match the map written before the event was versioned,
+// so version 0 events decode into it as well.
+type ClickEvent struct {
+	EventID       string    `json:"event_id"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	SchemaVersion int       `json:"schema_version"`
+
+	ShortID        string `json:"short_id"`
+	ShortCreatedBy string `json:"short_created_by"`
+	LongURL        string `json:"long_url"`
+	IPAddress      string `json:"ip_address"`
+	UserAgent      string `json:"user_agent"`
+	Referer        string `json:"referer"`
+	XForwardedFor  string `json:"x_forwarded_for"`
+	RequestHost    string `json:"request_host"`
+	Country        string `json:"country"`
+}
+
+// NewClickEvent with a fresh event ID, the current time and schema version
+func NewClickEvent() *ClickEvent {
+	return &ClickEvent{
+		EventID:       newEventID(),
+		OccurredAt:    time.Now().UTC(),
+		SchemaVersion: ClickEventSchemaVersion,
+	}
+}
+
+func (e *ClickEvent) Version() int {
+	return e.SchemaVersion
+}
+
+// newEventID returns a random (version 4) UUID
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+
+	return string(buf[:])
+}
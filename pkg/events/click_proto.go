@@ -0,0 +1,59 @@
+package events
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"shortbin/pkg/events/eventspb"
+)
+
+// MarshalProto encodes the event as the eventspb.ClickEvent message of
+// click.proto
+func (e *ClickEvent) MarshalProto() ([]byte, error) {
+	msg := &eventspb.ClickEvent{
+		EventId:        e.EventID,
+		SchemaVersion:  uint32(e.SchemaVersion),
+		ShortId:        e.ShortID,
+		ShortCreatedBy: e.ShortCreatedBy,
+		LongUrl:        e.LongURL,
+		IpAddress:      e.IPAddress,
+		UserAgent:      e.UserAgent,
+		Referer:        e.Referer,
+		XForwardedFor:  e.XForwardedFor,
+		RequestHost:    e.RequestHost,
+		Country:        e.Country,
+	}
+	if !e.OccurredAt.IsZero() {
+		msg.OccurredAt = timestamppb.New(e.OccurredAt)
+	}
+
+	return proto.Marshal(msg)
+}
+
+// UnmarshalProto decodes an eventspb.ClickEvent message, unknown fields are
+// skipped so that newer producers can add fields
+func (e *ClickEvent) UnmarshalProto(b []byte) error {
+	var msg eventspb.ClickEvent
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+
+	*e = ClickEvent{
+		EventID:        msg.GetEventId(),
+		SchemaVersion:  int(msg.GetSchemaVersion()),
+		ShortID:        msg.GetShortId(),
+		ShortCreatedBy: msg.GetShortCreatedBy(),
+		LongURL:        msg.GetLongUrl(),
+		IPAddress:      msg.GetIpAddress(),
+		UserAgent:      msg.GetUserAgent(),
+		Referer:        msg.GetReferer(),
+		XForwardedFor:  msg.GetXForwardedFor(),
+		RequestHost:    msg.GetRequestHost(),
+		Country:        msg.GetCountry(),
+	}
+	if msg.GetOccurredAt() != nil {
+		e.OccurredAt = msg.GetOccurredAt().AsTime()
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+// testdata/click_event.pb holds goldenEvent as encoded by an earlier release.
+// Regenerate it when a field is added, together with goldenEvent.
+var goldenEvent = ClickEvent{
+	EventID:        "0b7c1f9e-3d2a-4c8e-9f61-5a4b3c2d1e0f",
+	OccurredAt:     time.Date(2024, 5, 17, 9, 30, 15, 123456789, time.UTC),
+	SchemaVersion:  1,
+	ShortID:        "aB3dE5f",
+	ShortCreatedBy: "42",
+	LongURL:        "https://example.com/some/long/path?q=1",
+	IPAddress:      "203.0.113.7",
+	UserAgent:      "Mozilla/5.0",
+	Referer:        "https://referrer.example/",
+	XForwardedFor:  "203.0.113.7, 10.0.0.1",
+	RequestHost:    "sho.rt",
+	Country:        "DE",
+}
+
+// TestClickEventProtoGolden fails when the mapping of click_proto.go or a
+// change to click.proto breaks the encoding of events already in Kafka
+func TestClickEventProtoGolden(t *testing.T) {
+	golden, err := os.ReadFile("testdata/click_event.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := goldenEvent.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, golden) {
+		t.Errorf("MarshalProto does not match testdata/click_event.pb\n got: %x\nwant: %x", encoded, golden)
+	}
+
+	var decoded ClickEvent
+	if err = decoded.UnmarshalProto(golden); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != goldenEvent {
+		t.Errorf("UnmarshalProto of testdata/click_event.pb\n got: %+v\nwant: %+v", decoded, goldenEvent)
+	}
+}
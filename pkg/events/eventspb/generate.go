@@ -0,0 +1,5 @@
+// Package eventspb is the generated code of the protobuf encoding of the
+// events written to Kafka, defined in click.proto
+package eventspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative click.proto
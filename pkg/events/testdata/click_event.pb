@@ -0,0 +1,2 @@
+
+$0b7c1f9e-3d2a-4c8e-9f61-5a4b3c2d1e0fǜ:"aB3dE5f*422&https://example.com/some/long/path?q=1:203.0.113.7BMozilla/5.0Jhttps://referrer.example/R203.0.113.7, 10.0.0.1Zsho.rtbDE
\ No newline at end of file
@@ -0,0 +1,62 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/bits"
+	"sync/atomic"
+)
+
+// counterMultiplier is coprime with 62, so multiplying by it permutes the
+// id space and consecutive counter values do not produce neighbouring ids
+const counterMultiplier = 0x9E3779B97F4A7C15
+
+type counter struct {
+	next   atomic.Uint64
+	offset uint64
+}
+
+// NewCounter IDGenerator that scrambles a monotonic counter into base62.
+// The counter and offset start at random values so that restarted or
+// parallel instances do not walk the same sequence.
+func NewCounter() (IDGenerator, error) {
+	var seed [16]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+
+	g := &counter{offset: binary.BigEndian.Uint64(seed[8:])}
+	g.next.Store(binary.BigEndian.Uint64(seed[:8]))
+
+	return g, nil
+}
+
+func (g *counter) Generate(length int) (string, error) {
+	n := g.next.Add(1)
+
+	space, overflow := pow62(length)
+	if overflow {
+		return encodeBase62(n*counterMultiplier+g.offset, length)
+	}
+
+	// (n * multiplier + offset) mod 62^length is a bijection on the id space
+	hi, lo := bits.Mul64(n%space, counterMultiplier%space)
+	_, product := bits.Div64(hi%space, lo, space)
+	id := (product + g.offset%space) % space
+
+	return encodeBase62(id, length)
+}
+
+// pow62 returns 62^length and whether it overflows uint64
+func pow62(length int) (uint64, bool) {
+	result := uint64(1)
+	for i := 0; i < length; i++ {
+		hi, lo := bits.Mul64(result, 62)
+		if hi != 0 {
+			return 0, true
+		}
+		result = lo
+	}
+
+	return result, false
+}
@@ -0,0 +1,75 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	RandomStrategy    = "random"
+	CounterStrategy   = "counter"
+	SnowflakeStrategy = "snowflake"
+)
+
+const base62Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var ErrLengthTooShort = errors.New("requested id length too short for generator")
+
+// IDGenerator generates short IDs of the requested length
+type IDGenerator interface {
+	Generate(length int) (string, error)
+}
+
+// Config idgen
+type Config struct {
+	Strategy string
+	NodeID   int64
+	// MaxLength is the longest short ID allowed, refused when the strategy
+	// needs longer IDs. Unchecked when 0.
+	MaxLength int
+}
+
+// New IDGenerator for the configured strategy, defaults to random
+func New(cfg Config) (IDGenerator, error) {
+	switch cfg.Strategy {
+	case "", RandomStrategy:
+		return NewRandom(), nil
+	case CounterStrategy:
+		return NewCounter()
+	case SnowflakeStrategy:
+		if cfg.MaxLength > 0 && cfg.MaxLength < snowflakeLength {
+			return nil, fmt.Errorf("snowflake ids need a maximum short id length of at least %d", snowflakeLength)
+		}
+		return NewSnowflake(cfg.NodeID)
+	default:
+		return nil, fmt.Errorf("unknown id generator strategy: %s", cfg.Strategy)
+	}
+}
+
+// encodeBase62 encodes n and left pads it with the zero digit up to length
+func encodeBase62(n uint64, length int) (string, error) {
+	var buf [11]byte // 62^11 > 2^64
+	i := len(buf)
+	for {
+		i--
+		buf[i] = base62Chars[n%62]
+		n /= 62
+		if n == 0 {
+			break
+		}
+	}
+
+	encoded := buf[i:]
+	if len(encoded) > length {
+		return "", ErrLengthTooShort
+	}
+
+	result := make([]byte, length)
+	pad := length - len(encoded)
+	for j := 0; j < pad; j++ {
+		result[j] = base62Chars[0]
+	}
+	copy(result[pad:], encoded)
+
+	return string(result), nil
+}
@@ -0,0 +1,76 @@
+package idgen
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestCounterPermutation checks that multiplying by counterMultiplier is a
+// bijection on the id space of every length that does not overflow, which
+// holds when the multiplier is coprime with 62^length
+func TestCounterPermutation(t *testing.T) {
+	for length := 1; ; length++ {
+		space, overflow := pow62(length)
+		if overflow {
+			break
+		}
+
+		multiplier := new(big.Int).SetUint64(counterMultiplier % space)
+		gcd := new(big.Int).GCD(nil, nil, multiplier, new(big.Int).SetUint64(space))
+		if gcd.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("length %d: multiplier shares the factor %s with 62^%d", length, gcd, length)
+		}
+	}
+}
+
+// TestCounterExhaustive generates every id of the short lengths and checks
+// that none repeats, from an offset that overflows when added
+func TestCounterExhaustive(t *testing.T) {
+	for length := 1; length <= 3; length++ {
+		space, _ := pow62(length)
+
+		g := &counter{offset: math.MaxUint64 - 7}
+		g.next.Store(1 << 40)
+
+		seen := make(map[string]struct{}, space)
+		for i := uint64(0); i < space; i++ {
+			id, err := g.Generate(length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(id) != length {
+				t.Fatalf("length %d: got %q", length, id)
+			}
+			if _, ok := seen[id]; ok {
+				t.Fatalf("length %d: %q generated twice within %d ids", length, id, i+1)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func TestSnowflakeFitsMaxLength(t *testing.T) {
+	// snowflake IDs are non-negative int64, the largest one must fit
+	if _, err := encodeBase62(math.MaxInt64, snowflakeLength); err != nil {
+		t.Fatalf("largest snowflake id does not fit %d characters: %v", snowflakeLength, err)
+	}
+
+	g, err := New(Config{Strategy: SnowflakeStrategy, NodeID: maxNodeID, MaxLength: snowflakeLength})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2*maxSequence; i++ {
+		id, err := g.Generate(snowflakeLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(id) != snowflakeLength {
+			t.Fatalf("got %q, want %d characters", id, snowflakeLength)
+		}
+	}
+
+	if _, err = New(Config{Strategy: SnowflakeStrategy, MaxLength: snowflakeLength - 1}); err == nil {
+		t.Errorf("maximum length %d accepted, snowflake ids take up to %d characters", snowflakeLength-1, snowflakeLength)
+	}
+}
@@ -0,0 +1,39 @@
+package idgen
+
+import (
+	"crypto/rand"
+)
+
+// maxUnbiased is the largest multiple of len(base62Chars) that fits in a byte,
+// bytes at or above it are rejected to keep the distribution uniform
+const maxUnbiased = 256 - 256%len(base62Chars)
+
+type random struct{}
+
+// NewRandom IDGenerator backed by crypto/rand
+func NewRandom() IDGenerator {
+	return &random{}
+}
+
+func (g *random) Generate(length int) (string, error) {
+	result := make([]byte, 0, length)
+	buf := make([]byte, length+length/4)
+
+	for len(result) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			if int(b) >= maxUnbiased {
+				continue
+			}
+			result = append(result, base62Chars[int(b)%len(base62Chars)])
+			if len(result) == length {
+				break
+			}
+		}
+	}
+
+	return string(result), nil
+}
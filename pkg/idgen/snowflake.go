@@ -0,0 +1,66 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNodeID    = 1<<nodeBits - 1
+	maxSequence  = 1<<sequenceBits - 1
+
+	// snowflakeLength is the longest snowflake ID in base62, that of the
+	// largest int64
+	snowflakeLength = 11
+)
+
+// snowflakeEpoch 2024-01-01T00:00:00Z in milliseconds
+const snowflakeEpoch = 1704067200000
+
+type snowflake struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMs   int64
+	sequence int64
+}
+
+// NewSnowflake IDGenerator composing a millisecond timestamp, the node ID and
+// a per-millisecond sequence, encoded in base62 (10 characters until May
+// 2030, 11 after)
+func NewSnowflake(nodeID int64) (IDGenerator, error) {
+	if nodeID < 0 || nodeID > maxNodeID {
+		return nil, fmt.Errorf("snowflake node id must be between 0 and %d", maxNodeID)
+	}
+
+	return &snowflake{nodeID: nodeID}, nil
+}
+
+func (g *snowflake) Generate(length int) (string, error) {
+	g.mu.Lock()
+	now := time.Now().UnixMilli()
+	if now < g.lastMs {
+		// clock moved backwards, keep issuing from the last timestamp
+		now = g.lastMs
+	}
+
+	if now == g.lastMs {
+		g.sequence = (g.sequence + 1) & maxSequence
+		if g.sequence == 0 {
+			for now <= g.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Now().UnixMilli()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMs = now
+
+	id := (now-snowflakeEpoch)<<(nodeBits+sequenceBits) | g.nodeID<<sequenceBits | g.sequence
+	g.mu.Unlock()
+
+	return encodeBase62(uint64(id), length)
+}
@@ -15,9 +15,11 @@ const (
 	LoginTokenExpiryTime    = 5 * 60 * 60   // 5 hours
 	RefreshTokenExpiryTime  = 7 * 24 * 3600 // 7 days
 	ResetPasswordExpiryTime = 15 * 60       // 15 minutes
+	LinkUnlockExpiryTime    = 30 * 60       // 30 minutes
 	LoginTokenType          = "x-access"
 	RefreshTokenType        = "x-refresh"
 	ResetPasswordTokenType  = "x-reset"
+	LinkUnlockTokenType     = "x-unlock"
 )
 
 func GenerateAccessToken(payload map[string]interface{}, scope string) string {
@@ -26,6 +28,8 @@ func GenerateAccessToken(payload map[string]interface{}, scope string) string {
 		exp = time.Now().Add(time.Second * LoginTokenExpiryTime).Unix()
 	} else if scope == ResetPasswordTokenType {
 		exp = time.Now().Add(time.Second * ResetPasswordExpiryTime).Unix()
+	} else if scope == LinkUnlockTokenType {
+		exp = time.Now().Add(time.Second * LinkUnlockExpiryTime).Unix()
 	}
 
 	payload["type"] = scope
@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"shortbin/pkg/logger"
+)
+
+const (
+	minRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff = 30 * time.Second
+)
+
+// HandlerFunc handles one consumed message, the message is committed only
+// once the handler returns nil
+type HandlerFunc func(ctx context.Context, message Message) error
+
+// TypedHandler decodes messages with the encoder matching their content type
+// header before calling fn. Messages without the header are decoded with
+// defaultEncoder. Messages that can not be decoded are logged and skipped.
+func TypedHandler[T any](fn func(ctx context.Context, key string, value T, timestamp time.Time) error, defaultEncoder Encoder[T], encoders ...Encoder[T]) HandlerFunc {
+	encoders = append([]Encoder[T]{defaultEncoder}, encoders...)
+
+	return func(ctx context.Context, message Message) error {
+		encoder := defaultEncoder
+		if contentType := message.Headers[ContentTypeHeader]; contentType != "" {
+			encoder = nil
+			for _, e := range encoders {
+				if e.ContentType() == contentType {
+					encoder = e
+					break
+				}
+			}
+		}
+
+		if encoder == nil {
+			logger.Errorf("skipping message with unsupported content type, topic: %s, content type: %s", message.Topic, message.Headers[ContentTypeHeader])
+			return nil
+		}
+
+		value, err := encoder.Decode(message.Value)
+		if err != nil {
+			logger.Errorf("skipping undecodable message, topic: %s, error: %s", message.Topic, err)
+			return nil
+		}
+
+		return fn(ctx, message.Key, value, message.Time)
+	}
+}
+
+type IKafkaConsumer interface {
+	Consume(ctx context.Context, handle HandlerFunc) error
+	Close() error
+}
+
+type Consumer struct {
+	reader *kafka.Reader
+}
+
+type ConsumerConfig struct {
+	Broker  string
+	GroupID string
+	Topics  []string
+}
+
+func NewKafkaConsumer(cfg ConsumerConfig) IKafkaConsumer {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{cfg.Broker},
+		GroupID:     cfg.GroupID,
+		GroupTopics: cfg.Topics,
+	})
+
+	return &Consumer{
+		reader: r,
+	}
+}
+
+// Consume fetches messages until ctx is done. A failing handler is retried
+// with exponential backoff so that no message is skipped.
+func (kc *Consumer) Consume(ctx context.Context, handle HandlerFunc) error {
+	for {
+		msg, err := kc.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+
+		if err = kc.handleWithRetry(ctx, handle, &msg); err != nil {
+			return nil // ctx is done
+		}
+
+		if err = kc.reader.CommitMessages(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (kc *Consumer) handleWithRetry(ctx context.Context, handle HandlerFunc, msg *kafka.Message) error {
+	message := decodeMessage(msg)
+	backoff := minRetryBackoff
+	for {
+		err := handle(ctx, message)
+		if err == nil {
+			return nil
+		}
+
+		logger.Errorf("failed to handle message, topic: %s, offset: %d, retrying in %s, error: %s", msg.Topic, msg.Offset, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+func (kc *Consumer) Close() error {
+	return kc.reader.Close()
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	ContentTypeHeader   = "content-type"
+	SchemaVersionHeader = "schema-version"
+
+	JSONContentType     = "application/json"
+	ProtobufContentType = "application/x-protobuf"
+
+	JSONEncoding     = "json"
+	ProtobufEncoding = "protobuf"
+)
+
+// Encoder converts values of T to and from message payloads
+type Encoder[T any] interface {
+	ContentType() string
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// ProtoMessage is implemented by types with a hand written protobuf encoding
+type ProtoMessage interface {
+	MarshalProto() ([]byte, error)
+	UnmarshalProto(data []byte) error
+}
+
+// Versioned values have their schema version added as a message header
+type Versioned interface {
+	Version() int
+}
+
+// JSONEncoder encodes values with encoding/json
+type JSONEncoder[T any] struct{}
+
+func (JSONEncoder[T]) ContentType() string {
+	return JSONContentType
+}
+
+func (JSONEncoder[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONEncoder[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// ProtoEncoder encodes *T values with their protobuf encoding
+type ProtoEncoder[T any, PT interface {
+	*T
+	ProtoMessage
+}] struct{}
+
+func (ProtoEncoder[T, PT]) ContentType() string {
+	return ProtobufContentType
+}
+
+func (ProtoEncoder[T, PT]) Encode(value PT) ([]byte, error) {
+	return value.MarshalProto()
+}
+
+func (ProtoEncoder[T, PT]) Decode(data []byte) (PT, error) {
+	value := PT(new(T))
+	err := value.UnmarshalProto(data)
+	return value, err
+}
+
+// NewEncoder for *T by encoding name, defaults to JSON
+func NewEncoder[T any, PT interface {
+	*T
+	ProtoMessage
+}](encoding string) (Encoder[PT], error) {
+	switch encoding {
+	case "", JSONEncoding:
+		return JSONEncoder[PT]{}, nil
+	case ProtobufEncoding:
+		return ProtoEncoder[T, PT]{}, nil
+	default:
+		return nil, fmt.Errorf("unknown kafka encoding: %s", encoding)
+	}
+}
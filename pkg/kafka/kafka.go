@@ -2,55 +2,107 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
+	"strconv"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
-type IKafkaProducer interface {
-	Produce(ctx context.Context, topic string, key string, value map[string]string) error
+// Message to be produced to or consumed from a topic
+type Message struct {
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+	Time    time.Time
 }
 
-type Producer struct {
-	writer *kafka.Writer
+type IKafkaProducer interface {
+	Produce(ctx context.Context, messages ...Message) error
+	Close(ctx context.Context) error
 }
 
+// Config kafka producer
 type Config struct {
 	Broker string `mapstructure:"broker"`
+	// QueueSize is the number of messages buffered in memory
+	QueueSize int
+	// BatchSize is the maximum number of messages written in one request
+	BatchSize int
+	// Linger is how long a batch waits to fill up before being written
+	Linger time.Duration
+	// Policy when the queue is full, DropPolicy or BlockPolicy
+	Policy string
+	// Spool optionally stores messages that could not be written
+	Spool Spool
+	// Sink messages are written to, defaults to the Kafka broker
+	Sink EventSink
 }
 
-func NewKafkaProducer(cfg Config) IKafkaProducer {
-	w := &kafka.Writer{
-		Addr:     kafka.TCP(cfg.Broker),
-		Balancer: &kafka.LeastBytes{},
-	}
+// Publisher produces typed values
+type Publisher[T any] interface {
+	Publish(ctx context.Context, topic string, key string, value T) error
+}
 
-	return &Producer{
-		writer: w,
-	}
+// TypedProducer is a Publisher encoding values with an Encoder before handing
+// them to an IKafkaProducer
+type TypedProducer[T any] struct {
+	producer IKafkaProducer
+	encoder  Encoder[T]
 }
 
-type Publisher interface {
-	Produce(ctx context.Context, payload interface{}) error
+func NewTypedProducer[T any](producer IKafkaProducer, encoder Encoder[T]) *TypedProducer[T] {
+	return &TypedProducer[T]{
+		producer: producer,
+		encoder:  encoder,
+	}
 }
 
-func (kp *Producer) Produce(ctx context.Context, topic string, key string, value map[string]string) error {
-	message, err := kp.encodeMessage(topic, key, value)
+func (tp *TypedProducer[T]) Publish(ctx context.Context, topic string, key string, value T) error {
+	data, err := tp.encoder.Encode(value)
 	if err != nil {
 		return err
 	}
-	return kp.writer.WriteMessages(ctx, message)
+
+	headers := map[string]string{ContentTypeHeader: tp.encoder.ContentType()}
+	if v, ok := any(value).(Versioned); ok {
+		headers[SchemaVersionHeader] = strconv.Itoa(v.Version())
+	}
+
+	return tp.producer.Produce(ctx, Message{
+		Topic:   topic,
+		Key:     key,
+		Value:   data,
+		Headers: headers,
+	})
 }
 
-func (kp *Producer) encodeMessage(topic string, key string, value map[string]string) (kafka.Message, error) {
-	v, err := json.Marshal(value)
-	if err != nil {
-		return kafka.Message{}, err
+func encodeMessage(message *Message) kafka.Message {
+	headers := make([]kafka.Header, 0, len(message.Headers))
+	for k, v := range message.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
 	}
 
 	return kafka.Message{
-		Topic: topic,
-		Key:   []byte(key),
-		Value: v,
-	}, nil
+		Topic:   message.Topic,
+		Key:     []byte(message.Key),
+		Value:   message.Value,
+		Headers: headers,
+		Time:    message.Time,
+	}
+}
+
+func decodeMessage(message *kafka.Message) Message {
+	headers := make(map[string]string, len(message.Headers))
+	for _, h := range message.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	return Message{
+		Topic:   message.Topic,
+		Key:     string(message.Key),
+		Value:   message.Value,
+		Headers: headers,
+		Time:    message.Time,
+	}
 }
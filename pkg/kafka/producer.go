@@ -0,0 +1,219 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+
+	"shortbin/pkg/logger"
+)
+
+const (
+	DropPolicy  = "drop"
+	BlockPolicy = "block"
+
+	defaultQueueSize    = 10000
+	defaultBatchSize    = 100
+	defaultLinger       = 10 * time.Millisecond
+	writeContextTimeout = 10 * time.Second
+)
+
+var (
+	ErrQueueFull      = errors.New("kafka producer queue is full")
+	ErrProducerClosed = errors.New("kafka producer is closed")
+)
+
+// metrics are published under /debug/vars
+var metrics = expvar.NewMap("kafka_producer")
+
+// Spool durably stores messages that could not be written to Kafka so that
+// they can be relayed later
+type Spool interface {
+	Store(ctx context.Context, messages []Message) error
+}
+
+// Producer buffers messages in a bounded queue and writes them in batches to
+// its EventSink from a single background goroutine, so that producing never
+// waits on the sink unless the queue is full and the policy is BlockPolicy.
+// When a Spool is configured, failed batches and messages overflowing the
+// queue are stored in it instead of being dropped.
+type Producer struct {
+	sink  EventSink
+	spool Spool
+	cfg   Config
+
+	queue chan Message
+	// mu guards closed, Produce holds it for reading while sending to queue
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func NewKafkaProducer(cfg Config) IKafkaProducer {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Linger <= 0 {
+		cfg.Linger = defaultLinger
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = DropPolicy
+	}
+	if cfg.Sink == nil {
+		cfg.Sink = NewKafkaSink(cfg.Broker, cfg.BatchSize, cfg.Linger)
+	}
+
+	kp := &Producer{
+		sink:  cfg.Sink,
+		spool: cfg.Spool,
+		cfg:   cfg,
+		queue: make(chan Message, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+
+	metrics.Set("queue_depth", expvar.Func(func() any {
+		return len(kp.queue)
+	}))
+
+	go kp.run()
+
+	return kp
+}
+
+// Produce enqueues the messages. With DropPolicy messages that do not fit in
+// the queue are spooled, or dropped returning ErrQueueFull without a spool.
+// With BlockPolicy it waits for room until ctx is done.
+func (kp *Producer) Produce(ctx context.Context, messages ...Message) error {
+	kp.mu.RLock()
+	defer kp.mu.RUnlock()
+
+	if kp.closed {
+		return ErrProducerClosed
+	}
+
+	for i, msg := range messages {
+		if kp.cfg.Policy == BlockPolicy {
+			select {
+			case kp.queue <- msg:
+			case <-ctx.Done():
+				metrics.Add("dropped", int64(len(messages)-i))
+				return ctx.Err()
+			}
+		} else {
+			select {
+			case kp.queue <- msg:
+			default:
+				return kp.overflow(ctx, messages[i:])
+			}
+		}
+
+		metrics.Add("enqueued", 1)
+	}
+
+	return nil
+}
+
+// Close stops accepting messages and waits until the queued messages are
+// written or ctx is done
+func (kp *Producer) Close(ctx context.Context) error {
+	kp.mu.Lock()
+	if !kp.closed {
+		kp.closed = true
+		close(kp.queue)
+	}
+	kp.mu.Unlock()
+
+	select {
+	case <-kp.done:
+	case <-ctx.Done():
+		metrics.Add("dropped", int64(len(kp.queue)))
+		return ctx.Err()
+	}
+
+	return kp.sink.Close()
+}
+
+func (kp *Producer) run() {
+	defer close(kp.done)
+
+	batch := make([]Message, 0, kp.cfg.BatchSize)
+	linger := time.NewTimer(kp.cfg.Linger)
+	linger.Stop()
+
+	for {
+		select {
+		case msg, ok := <-kp.queue:
+			if !ok {
+				kp.write(batch)
+				return
+			}
+
+			if len(batch) == 0 {
+				linger.Reset(kp.cfg.Linger)
+			}
+			batch = append(batch, msg)
+			if len(batch) < kp.cfg.BatchSize {
+				continue
+			}
+			linger.Stop()
+		case <-linger.C:
+		}
+
+		kp.write(batch)
+		batch = batch[:0]
+	}
+}
+
+func (kp *Producer) write(batch []Message) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeContextTimeout)
+	defer cancel()
+
+	if err := kp.sink.Write(ctx, batch); err != nil {
+		metrics.Add("failed", int64(len(batch)))
+		logger.Errorf("failed to write %d messages to event sink: %v", len(batch), err)
+
+		// the write may have used up its whole timeout, e.g. with Kafka
+		// unreachable, so spooling gets a timeout of its own
+		spoolCtx, cancel := context.WithTimeout(context.Background(), writeContextTimeout)
+		defer cancel()
+		_ = kp.store(spoolCtx, batch)
+		return
+	}
+
+	metrics.Add("written", int64(len(batch)))
+}
+
+// overflow handles messages that did not fit in the queue
+func (kp *Producer) overflow(ctx context.Context, messages []Message) error {
+	if kp.spool == nil {
+		metrics.Add("dropped", int64(len(messages)))
+		return ErrQueueFull
+	}
+
+	return kp.store(ctx, messages)
+}
+
+func (kp *Producer) store(ctx context.Context, messages []Message) error {
+	if kp.spool == nil {
+		metrics.Add("dropped", int64(len(messages)))
+		return nil
+	}
+
+	if err := kp.spool.Store(ctx, messages); err != nil {
+		metrics.Add("dropped", int64(len(messages)))
+		logger.Errorf("failed to spool %d messages: %v", len(messages), err)
+		return err
+	}
+
+	metrics.Add("spooled", int64(len(messages)))
+	return nil
+}
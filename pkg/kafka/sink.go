@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// EventSink is where the producer writes its batches of messages
+type EventSink interface {
+	Write(ctx context.Context, messages []Message) error
+	Close() error
+}
+
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink writes messages to the broker
+func NewKafkaSink(broker string, batchSize int, batchTimeout time.Duration) EventSink {
+	w := &kafka.Writer{
+		Addr:         kafka.TCP(broker),
+		Balancer:     &kafka.LeastBytes{},
+		BatchSize:    batchSize,
+		BatchTimeout: batchTimeout,
+	}
+
+	return &kafkaSink{
+		writer: w,
+	}
+}
+
+func (ks *kafkaSink) Write(ctx context.Context, messages []Message) error {
+	kafkaMessages := make([]kafka.Message, len(messages))
+	for i := range messages {
+		kafkaMessages[i] = encodeMessage(&messages[i])
+	}
+
+	return ks.writer.WriteMessages(ctx, kafkaMessages...)
+}
+
+func (ks *kafkaSink) Close() error {
+	return ks.writer.Close()
+}
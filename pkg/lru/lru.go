@@ -0,0 +1,96 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a size bounded, least recently used cache with a TTL per entry.
+// It is safe for concurrent use.
+type Cache[V any] struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// New Cache holding at most size entries
+func New[V any](size int) *Cache[V] {
+	return &Cache[V]{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// Get the value at key, expired entries are removed and reported as missing
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*entry[V])
+	if time.Now().After(e.expiresAt) {
+		c.remove(el)
+		return zero, false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set the value at key for ttl, evicting the least recently used entry when
+// the cache is full. It reports whether an entry was evicted.
+func (c *Cache[V]) Set(key string, value V, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[V])
+		e.value, e.expiresAt = value, expiresAt
+		c.ll.MoveToFront(el)
+		return false
+	}
+
+	c.entries[key] = c.ll.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	if c.ll.Len() <= c.size {
+		return false
+	}
+
+	c.remove(c.ll.Back())
+	return true
+}
+
+func (c *Cache[V]) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.entries, el.Value.(*entry[V]).key)
+}
@@ -8,26 +8,34 @@ import (
 	"shortbin/pkg/jwt"
 )
 
-func OptionalJWTAuth() gin.HandlerFunc {
+func OptionalJWTAuth(apiKeys APIKeyValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.GetHeader("Authorization") != "" {
-			JWTAuth()(c)
+		if c.GetHeader("Authorization") != "" || c.GetHeader(APIKeyHeader) != "" {
+			JWTAuth(apiKeys)(c)
 		} else {
 			c.Next()
 		}
 	}
 }
 
-func JWTAuth() gin.HandlerFunc {
-	return JWT(jwt.LoginTokenType)
+// JWTAuth accepts access tokens, and API keys in the X-API-Key header when
+// apiKeys is not nil
+func JWTAuth(apiKeys APIKeyValidator) gin.HandlerFunc {
+	return JWT(jwt.LoginTokenType, apiKeys)
 }
 
 func JWTRefresh() gin.HandlerFunc {
-	return JWT(jwt.RefreshTokenType)
+	return JWT(jwt.RefreshTokenType, nil)
 }
 
-func JWT(tokenType string) gin.HandlerFunc {
+func JWT(tokenType string, apiKeys APIKeyValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API keys stand in for access tokens only
+		if key := c.GetHeader(APIKeyHeader); key != "" && tokenType == jwt.LoginTokenType && apiKeys != nil {
+			apiKeyAuth(c, apiKeys, key)
+			return
+		}
+
 		token := c.GetHeader("Authorization")
 		if token == "" {
 			c.JSON(http.StatusUnauthorized, nil)
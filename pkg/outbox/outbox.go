@@ -0,0 +1,176 @@
+package outbox
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"shortbin/pkg/kafka"
+	"shortbin/pkg/logger"
+)
+
+const (
+	defaultBatchSize    = 500
+	defaultPollInterval = 5 * time.Second
+	maxBackoff          = 5 * time.Minute
+)
+
+// metrics are published under /debug/vars
+var (
+	metrics = expvar.NewMap("click_outbox")
+	depth   = new(expvar.Int)
+)
+
+func init() {
+	metrics.Set("depth", depth)
+}
+
+// Config outbox relay
+type Config struct {
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+// Outbox is a kafka.Spool backed by the click_outbox table
+type Outbox struct {
+	db *pgxpool.Pool
+}
+
+func New(db *pgxpool.Pool) *Outbox {
+	return &Outbox{db: db}
+}
+
+func (o *Outbox) Store(ctx context.Context, messages []kafka.Message) error {
+	rows := make([][]interface{}, len(messages))
+	for i, msg := range messages {
+		rows[i] = []interface{}{msg.Topic, msg.Key, msg.Value, msg.Headers}
+	}
+
+	_, err := o.db.CopyFrom(
+		ctx,
+		pgx.Identifier{"click_outbox"},
+		[]string{"topic", "key", "value", "headers"},
+		pgx.CopyFromRows(rows),
+	)
+	return err
+}
+
+// Relay republishes spooled messages to Kafka in insertion order
+type Relay struct {
+	db   *pgxpool.Pool
+	sink kafka.EventSink
+	cfg  Config
+}
+
+func NewRelay(db *pgxpool.Pool, sink kafka.EventSink, cfg Config) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+
+	return &Relay{
+		db:   db,
+		sink: sink,
+		cfg:  cfg,
+	}
+}
+
+// Run relays until ctx is done. While Kafka or the database fail the relay
+// backs off exponentially, starting from the poll interval.
+func (r *Relay) Run(ctx context.Context) {
+	defer func() {
+		if err := r.sink.Close(); err != nil {
+			logger.Error("Failed to close outbox relay sink ", err)
+		}
+	}()
+
+	wait := r.cfg.PollInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		relayed, err := r.relayBatch(ctx)
+		r.updateDepth(ctx)
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			wait = min(max(2*wait, r.cfg.PollInterval), maxBackoff)
+			logger.Errorf("failed to relay outbox, retrying in %s: %v", wait, err)
+		case relayed == r.cfg.BatchSize:
+			// more messages are waiting, continue right away
+			wait = 0
+		default:
+			wait = r.cfg.PollInterval
+		}
+	}
+}
+
+// relayBatch writes the oldest spooled messages to Kafka and deletes them in
+// the same transaction, rows are locked so that replicas relay disjoint batches
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	var relayed int
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT id, topic, key, value, headers FROM click_outbox ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`
+		rows, err := tx.Query(ctx, query, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		var ids []int64
+		var messages []kafka.Message
+		for rows.Next() {
+			var id int64
+			var msg kafka.Message
+			if err = rows.Scan(&id, &msg.Topic, &msg.Key, &msg.Value, &msg.Headers); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			messages = append(messages, msg)
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		if err = r.sink.Write(ctx, messages); err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, `DELETE FROM click_outbox WHERE id = ANY($1)`, ids); err != nil {
+			return err
+		}
+
+		relayed = len(messages)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	metrics.Add("relayed", int64(relayed))
+	return relayed, nil
+}
+
+func (r *Relay) updateDepth(ctx context.Context) {
+	var count int64
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM click_outbox`).Scan(&count); err != nil {
+		return
+	}
+
+	depth.Set(count)
+}
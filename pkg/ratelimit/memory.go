@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"shortbin/pkg/lru"
+)
+
+type bucket struct {
+	mu     sync.Mutex
+	tokens float64
+	ts     time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets *lru.Cache[*bucket]
+}
+
+// NewMemoryStore keeps up to size token buckets in process, the least
+// recently used are dropped first
+func NewMemoryStore(size int) Store {
+	return &memoryStore{buckets: lru.New[*bucket](size)}
+}
+
+func (s *memoryStore) Take(key string, policy Policy) (Result, error) {
+	rate := float64(policy.Limit) / float64(policy.Period)
+	burst := float64(policy.Burst)
+	now := time.Now()
+
+	s.mu.Lock()
+	b, ok := s.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: burst, ts: now}
+	}
+	// a bucket left alone for the time it takes to refill is full again,
+	// so it only needs to be kept for that long
+	s.buckets.Set(key, b, time.Duration(burst/rate))
+	s.mu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(burst, b.tokens+float64(now.Sub(b.ts))*rate)
+	b.ts = now
+
+	res := Result{Limit: policy.Limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration(math.Ceil((burst - b.tokens) / rate))
+
+	return res, nil
+}
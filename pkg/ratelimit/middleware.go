@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"shortbin/pkg/config"
+	"shortbin/pkg/logger"
+	"shortbin/pkg/middleware"
+	"shortbin/pkg/response"
+)
+
+const (
+	KeyByIP     = "ip"
+	KeyByUser   = "user"
+	KeyByAPIKey = "api_key"
+)
+
+// Middleware limits requests with the policy configured under name. It is a
+// no-op when rate limiting is disabled or the policy is not configured.
+// Keying by user or API key falls back to the client IP for anonymous
+// requests, and has to run after the auth middleware.
+func Middleware(limiter *Limiter, name string) gin.HandlerFunc {
+	cfg := config.GetConfig().RateLimit
+	policyCfg, ok := cfg.Policies[name]
+	if !cfg.Enabled || !ok || policyCfg.Limit <= 0 || policyCfg.Period <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	policy := Policy{
+		Limit:  policyCfg.Limit,
+		Period: policyCfg.Period * time.Second,
+		Burst:  policyCfg.Burst,
+	}
+
+	return func(c *gin.Context) {
+		res, err := limiter.Take(name+":"+clientKey(c, policyCfg.KeyBy), policy)
+		if err != nil {
+			// never turn a limiter failure into an outage
+			logger.Error("rate limiter failed: ", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+			response.Error(c, http.StatusTooManyRequests, errors.New(response.RateLimited), response.RateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context, keyBy string) string {
+	switch keyBy {
+	case KeyByAPIKey:
+		// only keys the auth middleware validated, a made up header on every
+		// request would otherwise get a fresh bucket every time
+		if keyID := middleware.APIKeyID(c); keyID != "" {
+			return "key:" + keyID
+		}
+		fallthrough
+	case KeyByUser:
+		if userID := c.GetString("userId"); userID != "" {
+			return "user:" + userID
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+// seconds rounds d up to whole seconds, as used by the rate limit headers
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
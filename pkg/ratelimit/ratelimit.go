@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"sync/atomic"
+	"time"
+
+	"shortbin/pkg/logger"
+	"shortbin/pkg/redis"
+)
+
+const defaultMemorySize = 100000
+
+// Policy allows Limit requests per Period with bursts of up to Burst
+type Policy struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// Result of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request is allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps token buckets
+type Store interface {
+	Take(key string, policy Policy) (Result, error)
+}
+
+// Limiter takes tokens from Redis so that limits hold across instances, and
+// falls back to an in-process store while Redis is unreachable
+type Limiter struct {
+	redis    Store
+	memory   Store
+	degraded atomic.Bool
+}
+
+// New Limiter backed by redis, memorySize bounds the number of buckets kept
+// in process for the fallback
+func New(redis redis.IRedis, memorySize int) *Limiter {
+	if memorySize <= 0 {
+		memorySize = defaultMemorySize
+	}
+
+	return &Limiter{
+		redis:  NewRedisStore(redis),
+		memory: NewMemoryStore(memorySize),
+	}
+}
+
+func (l *Limiter) Take(key string, policy Policy) (Result, error) {
+	if policy.Burst <= 0 {
+		policy.Burst = policy.Limit
+	}
+
+	res, err := l.redis.Take(key, policy)
+	if err == nil {
+		if l.degraded.CompareAndSwap(true, false) {
+			logger.Info("rate limiter is using redis again")
+		}
+		return res, nil
+	}
+
+	if l.degraded.CompareAndSwap(false, true) {
+		logger.Error("rate limiter falling back to memory: ", err)
+	}
+	return l.memory.Take(key, policy)
+}
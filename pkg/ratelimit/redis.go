@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+
+	"shortbin/pkg/redis"
+)
+
+// tokenBucket takes a token from the bucket at KEYS[1] that refills ARGV[1]
+// tokens per millisecond up to ARGV[2]. It uses the Redis clock so that
+// instances with skewed clocks share one view of the bucket. Returns
+// allowed, remaining, retry after and reset in milliseconds.
+const tokenBucket = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+local reset = math.ceil((burst - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.max(reset, 1))
+
+return {allowed, math.floor(tokens), retry, reset}
+`
+
+const keyPrefix = "ratelimit:"
+
+type redisStore struct {
+	redis redis.IRedis
+}
+
+// NewRedisStore keeps token buckets in Redis
+func NewRedisStore(redis redis.IRedis) Store {
+	return &redisStore{redis: redis}
+}
+
+func (s *redisStore) Take(key string, policy Policy) (Result, error) {
+	rate := float64(policy.Limit) / float64(policy.Period.Milliseconds())
+
+	reply, err := s.redis.Eval(tokenBucket, []string{keyPrefix + key}, rate, policy.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected token bucket reply: %v", reply)
+	}
+
+	ints := make([]int64, len(values))
+	for i, v := range values {
+		if ints[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("unexpected token bucket reply: %v", reply)
+		}
+	}
+
+	return Result{
+		Allowed:    ints[0] == 1,
+		Limit:      policy.Limit,
+		Remaining:  int(ints[1]),
+		RetryAfter: time.Duration(ints[2]) * time.Millisecond,
+		Reset:      time.Duration(ints[3]) * time.Millisecond,
+	}, nil
+}
@@ -22,6 +22,15 @@ type IRedis interface {
 	GetByRefreshingExpiry(key string, value interface{}) error
 	Set(key string, value interface{}, expiryTime time.Duration) error
 	SetExpiry(key string, expiryTime time.Duration) error
+	Delete(keys ...string) error
+	Incr(key string, expiryTime time.Duration) (int64, error)
+	Exists(key string) (bool, error)
+	SetBits(key string, offsets []uint64) error
+	GetBits(key string, offsets []uint64) ([]bool, error)
+	Eval(script string, keys []string, args ...interface{}) (interface{}, error)
+	Publish(channel string, message string) error
+	Subscribe(ctx context.Context, channel string, handler func(message string)) error
+	Close() error
 }
 
 // Config redis
@@ -33,8 +42,15 @@ type Config struct {
 
 const NilReturn = goredis.Nil
 
+// Refresher is implemented by values read with GetByRefreshingExpiry that
+// decide their own expiry, given the configured one. The expiry is left
+// untouched when it returns zero or less.
+type Refresher interface {
+	RefreshTTL(ttl time.Duration) time.Duration
+}
+
 type redis struct {
-	cmd goredis.Cmdable
+	cmd goredis.UniversalClient
 }
 
 // New Redis interface with config
@@ -85,18 +101,26 @@ func (r *redis) GetByRefreshingExpiry(key string, value interface{}) error {
 		return err
 	}
 
+	err = json.Unmarshal([]byte(strValue), value)
+	if err != nil {
+		return err
+	}
+
+	ttl := config.GetConfig().Redis.TTL * time.Minute
+	if refresher, ok := value.(Refresher); ok {
+		ttl = refresher.RefreshTTL(ttl)
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
 	go func() {
-		err := r.SetExpiry(key, config.GetConfig().Redis.TTL*time.Minute)
+		err := r.SetExpiry(key, ttl)
 		if err != nil {
 			logger.Error("error refreshing ttl: ", err)
 		}
 	}()
 
-	err = json.Unmarshal([]byte(strValue), value)
-	if err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -124,3 +148,130 @@ func (r *redis) SetExpiry(key string, expiryTime time.Duration) error {
 
 	return nil
 }
+
+func (r *redis) Delete(keys ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	return r.cmd.Del(ctx, keys...).Err()
+}
+
+func (r *redis) Close() error {
+	return r.cmd.Close()
+}
+
+// incrWindow increments the counter at KEYS[1] and sets its expiry to ARGV[1]
+// milliseconds when it has none, in one step so that a counter can not be
+// left without an expiry
+const incrWindow = `
+local count = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`
+
+// Incr increments the counter at key, the expiry is only set when the counter
+// is created so that it marks the end of a fixed window
+func (r *redis) Incr(key string, expiryTime time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	return r.cmd.Eval(ctx, incrWindow, []string{key}, expiryTime.Milliseconds()).Int64()
+}
+
+func (r *redis) Exists(key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	count, err := r.cmd.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SetBits sets the bits at offsets of the bitmap at key in one round trip
+func (r *redis) SetBits(key string, offsets []uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	_, err := r.cmd.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, offset := range offsets {
+			pipe.SetBit(ctx, key, int64(offset), 1)
+		}
+		return nil
+	})
+	return err
+}
+
+// GetBits reads the bits at offsets of the bitmap at key in one round trip
+func (r *redis) GetBits(key string, offsets []uint64) ([]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	cmds := make([]*goredis.IntCmd, len(offsets))
+	_, err := r.cmd.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, offset := range offsets {
+			cmds[i] = pipe.GetBit(ctx, key, int64(offset))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bits := make([]bool, len(cmds))
+	for i, cmd := range cmds {
+		bits[i] = cmd.Val() == 1
+	}
+
+	return bits, nil
+}
+
+func (r *redis) Publish(channel string, message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	return r.cmd.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe calls handler for every message published to channel until ctx
+// is done. It returns once the subscription is confirmed.
+func (r *redis) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	pubsub := r.cmd.Subscribe(ctx, channel)
+
+	initCtx, cancel := context.WithTimeout(ctx, ContextTimeout*time.Second)
+	defer cancel()
+	if _, err := pubsub.Receive(initCtx); err != nil {
+		_ = pubsub.Close()
+		return err
+	}
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				handler(msg.Payload)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *redis) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ContextTimeout*time.Second)
+	defer cancel()
+
+	return r.cmd.Eval(ctx, script, keys, args...).Result()
+}
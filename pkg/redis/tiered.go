@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"strings"
+	"time"
+
+	"shortbin/pkg/logger"
+	"shortbin/pkg/lru"
+)
+
+const (
+	defaultLocalSize    = 10000
+	defaultLocalTTL     = 30 * time.Second
+	defaultInvalidation = "cache:invalidate"
+)
+
+var localMetrics = expvar.NewMap("local_cache")
+
+// TieredConfig of the in-process tier
+type TieredConfig struct {
+	Size int
+	TTL  time.Duration
+	// Channel deletes are published on so other instances drop their copy
+	Channel string
+}
+
+// tiered keeps recently read values in process in front of Redis. Entries
+// live at most TTL locally, so a hot key costs one Redis round trip per TTL
+// per instance. Only GetByRefreshingExpiry reads from the local tier, Get
+// always reads Redis as it is used for counters that must not go stale.
+type tiered struct {
+	IRedis
+	local   *lru.Cache[[]byte]
+	ttl     time.Duration
+	channel string
+}
+
+// NewTiered wraps remote with an in-process LRU tier. Deletes are propagated
+// to the other instances over Redis pub/sub until ctx is done.
+func NewTiered(ctx context.Context, remote IRedis, cfg TieredConfig) (IRedis, error) {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultLocalSize
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultLocalTTL
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = defaultInvalidation
+	}
+
+	t := &tiered{
+		IRedis:  remote,
+		local:   lru.New[[]byte](cfg.Size),
+		ttl:     cfg.TTL,
+		channel: cfg.Channel,
+	}
+
+	if err := remote.Subscribe(ctx, cfg.Channel, t.invalidate); err != nil {
+		return nil, err
+	}
+
+	localMetrics.Set("size", expvar.Func(func() any {
+		return t.local.Len()
+	}))
+
+	return t, nil
+}
+
+// GetByRefreshingExpiry only refreshes the Redis expiry on a local miss,
+// which happens at least once per local TTL for a hot key
+func (t *tiered) GetByRefreshingExpiry(key string, value interface{}) error {
+	if t.getLocal(key, value) {
+		return nil
+	}
+
+	if err := t.IRedis.GetByRefreshingExpiry(key, value); err != nil {
+		return err
+	}
+
+	t.setLocal(key, value, t.ttl)
+	return nil
+}
+
+func (t *tiered) Set(key string, value interface{}, expiryTime time.Duration) error {
+	if err := t.IRedis.Set(key, value, expiryTime); err != nil {
+		return err
+	}
+
+	ttl := t.ttl
+	if expiryTime > 0 && expiryTime < ttl {
+		ttl = expiryTime
+	}
+	t.setLocal(key, value, ttl)
+
+	return nil
+}
+
+func (t *tiered) Delete(keys ...string) error {
+	for _, key := range keys {
+		t.local.Delete(key)
+	}
+
+	if err := t.IRedis.Delete(keys...); err != nil {
+		return err
+	}
+
+	return t.IRedis.Publish(t.channel, strings.Join(keys, " "))
+}
+
+// invalidate drops the space separated keys of an invalidation message
+func (t *tiered) invalidate(message string) {
+	for _, key := range strings.Fields(message) {
+		t.local.Delete(key)
+	}
+}
+
+func (t *tiered) getLocal(key string, value interface{}) bool {
+	data, ok := t.local.Get(key)
+	if !ok {
+		localMetrics.Add("misses", 1)
+		return false
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		t.local.Delete(key)
+		localMetrics.Add("misses", 1)
+		return false
+	}
+
+	localMetrics.Add("hits", 1)
+	return true
+}
+
+func (t *tiered) setLocal(key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		logger.Error("error caching locally: ", err)
+		return
+	}
+
+	if t.local.Set(key, data, ttl) {
+		localMetrics.Add("evictions", 1)
+	}
+}
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	"shortbin/pkg/config"
@@ -18,17 +20,49 @@ const (
 	IDLengthNotInRange = "id length not in range"
 	UserNotFound       = "user not found"
 	NoRowsInResultSet  = "no rows in result set"
+	AliasAlreadyExists = "alias already exists"
+	AliasReserved      = "alias is reserved"
+	InvalidAlias       = "alias contains invalid characters"
+	InvalidCursor      = "invalid cursor"
+	LinkExpired        = "link expired"
+	WrongPassword      = "wrong password"
+	TooManyAttempts    = "too many attempts"
+	RateLimited        = "rate limit exceeded"
+	PlanNotFound       = "plan not found"
+	QuotaExceeded      = "link quota exceeded"
+	FeatureNotInPlan   = "feature not included in plan"
+	ExpiryNotInPlan    = "expiry exceeds plan limit"
+	InvalidAPIKey      = "invalid api key"
+	TooManyItems       = "too many items"
 )
 
+// ErrorResponse is the body of every error response, details says which
+// parameter is invalid and debug holds the underlying error outside
+// production
+type ErrorResponse struct {
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+	Debug   string `json:"debug,omitempty"`
+}
+
 func Error(c *gin.Context, status int, err error, message string) {
-	errorRes := map[string]interface{}{
-		"message": message,
+	errorRes := ErrorResponse{
+		Message: message,
 	}
 
 	if config.GetConfig().Environment != config.ProductionEnv {
-		errorRes["debug"] = err.Error()
+		errorRes.Debug = err.Error()
 	}
 
 	c.JSON(status, errorRes)
 	// c.JSON(status, Response{Error: errorRes})
 }
+
+// Invalid responds 400 InvalidParameters to a failed validation, with its
+// translated message as details
+func Invalid(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Message: InvalidParameters,
+		Details: err.Error(),
+	})
+}
@@ -0,0 +1,5 @@
+// Package shortbinpb is the generated code of the ShortbinService gRPC API
+// defined in shortbin.proto
+package shortbinpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative shortbin.proto
@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: shortbin.proto
+
+package shortbinpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ShortbinService_Create_FullMethodName     = "/shortbin.api.v1.ShortbinService/Create"
+	ShortbinService_Resolve_FullMethodName    = "/shortbin.api.v1.ShortbinService/Resolve"
+	ShortbinService_GetLink_FullMethodName    = "/shortbin.api.v1.ShortbinService/GetLink"
+	ShortbinService_ListLinks_FullMethodName  = "/shortbin.api.v1.ShortbinService/ListLinks"
+	ShortbinService_DeleteLink_FullMethodName = "/shortbin.api.v1.ShortbinService/DeleteLink"
+)
+
+// ShortbinServiceClient is the client API for ShortbinService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ShortbinService is the gRPC counterpart of the HTTP API. Every call needs
+// the access token of the HTTP API in the "authorization" metadata, as
+// "Bearer <token>".
+type ShortbinServiceClient interface {
+	// Create a short link, optionally with a custom alias
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Link, error)
+	// Resolve a short ID to its long URL, without recording a click
+	Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error)
+	// GetLink returns one of the caller's links
+	GetLink(ctx context.Context, in *GetLinkRequest, opts ...grpc.CallOption) (*Link, error)
+	// ListLinks pages through the caller's links, newest first
+	ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error)
+	// DeleteLink deletes one of the caller's links
+	DeleteLink(ctx context.Context, in *DeleteLinkRequest, opts ...grpc.CallOption) (*DeleteLinkResponse, error)
+}
+
+type shortbinServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShortbinServiceClient(cc grpc.ClientConnInterface) ShortbinServiceClient {
+	return &shortbinServiceClient{cc}
+}
+
+func (c *shortbinServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Link, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Link)
+	err := c.cc.Invoke(ctx, ShortbinService_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortbinServiceClient) Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveResponse)
+	err := c.cc.Invoke(ctx, ShortbinService_Resolve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortbinServiceClient) GetLink(ctx context.Context, in *GetLinkRequest, opts ...grpc.CallOption) (*Link, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Link)
+	err := c.cc.Invoke(ctx, ShortbinService_GetLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortbinServiceClient) ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLinksResponse)
+	err := c.cc.Invoke(ctx, ShortbinService_ListLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortbinServiceClient) DeleteLink(ctx context.Context, in *DeleteLinkRequest, opts ...grpc.CallOption) (*DeleteLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLinkResponse)
+	err := c.cc.Invoke(ctx, ShortbinService_DeleteLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShortbinServiceServer is the server API for ShortbinService service.
+// All implementations must embed UnimplementedShortbinServiceServer
+// for forward compatibility.
+//
+// ShortbinService is the gRPC counterpart of the HTTP API. Every call needs
+// the access token of the HTTP API in the "authorization" metadata, as
+// "Bearer <token>".
+type ShortbinServiceServer interface {
+	// Create a short link, optionally with a custom alias
+	Create(context.Context, *CreateRequest) (*Link, error)
+	// Resolve a short ID to its long URL, without recording a click
+	Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error)
+	// GetLink returns one of the caller's links
+	GetLink(context.Context, *GetLinkRequest) (*Link, error)
+	// ListLinks pages through the caller's links, newest first
+	ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error)
+	// DeleteLink deletes one of the caller's links
+	DeleteLink(context.Context, *DeleteLinkRequest) (*DeleteLinkResponse, error)
+	mustEmbedUnimplementedShortbinServiceServer()
+}
+
+// UnimplementedShortbinServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedShortbinServiceServer struct{}
+
+func (UnimplementedShortbinServiceServer) Create(context.Context, *CreateRequest) (*Link, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedShortbinServiceServer) Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+func (UnimplementedShortbinServiceServer) GetLink(context.Context, *GetLinkRequest) (*Link, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLink not implemented")
+}
+func (UnimplementedShortbinServiceServer) ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLinks not implemented")
+}
+func (UnimplementedShortbinServiceServer) DeleteLink(context.Context, *DeleteLinkRequest) (*DeleteLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLink not implemented")
+}
+func (UnimplementedShortbinServiceServer) mustEmbedUnimplementedShortbinServiceServer() {}
+func (UnimplementedShortbinServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeShortbinServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ShortbinServiceServer will
+// result in compilation errors.
+type UnsafeShortbinServiceServer interface {
+	mustEmbedUnimplementedShortbinServiceServer()
+}
+
+func RegisterShortbinServiceServer(s grpc.ServiceRegistrar, srv ShortbinServiceServer) {
+	// If the following call pancis, it indicates UnimplementedShortbinServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ShortbinService_ServiceDesc, srv)
+}
+
+func _ShortbinService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortbinServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortbinService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortbinServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortbinService_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortbinServiceServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortbinService_Resolve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortbinServiceServer).Resolve(ctx, req.(*ResolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortbinService_GetLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortbinServiceServer).GetLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortbinService_GetLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortbinServiceServer).GetLink(ctx, req.(*GetLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortbinService_ListLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortbinServiceServer).ListLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortbinService_ListLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortbinServiceServer).ListLinks(ctx, req.(*ListLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortbinService_DeleteLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortbinServiceServer).DeleteLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortbinService_DeleteLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortbinServiceServer).DeleteLink(ctx, req.(*DeleteLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ShortbinService_ServiceDesc is the grpc.ServiceDesc for ShortbinService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ShortbinService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shortbin.api.v1.ShortbinService",
+	HandlerType: (*ShortbinServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ShortbinService_Create_Handler,
+		},
+		{
+			MethodName: "Resolve",
+			Handler:    _ShortbinService_Resolve_Handler,
+		},
+		{
+			MethodName: "GetLink",
+			Handler:    _ShortbinService_GetLink_Handler,
+		},
+		{
+			MethodName: "ListLinks",
+			Handler:    _ShortbinService_ListLinks_Handler,
+		},
+		{
+			MethodName: "DeleteLink",
+			Handler:    _ShortbinService_DeleteLink_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shortbin.proto",
+}
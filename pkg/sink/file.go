@@ -0,0 +1,96 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"shortbin/pkg/kafka"
+)
+
+// fileRecord is one line of a JSON lines sink. JSON payloads are embedded as
+// is, any other payload is base64 encoded.
+type fileRecord struct {
+	Topic   string            `json:"topic"`
+	Key     string            `json:"key"`
+	Value   json.RawMessage   `json:"value,omitempty"`
+	Binary  []byte            `json:"binary,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+type fileSink struct {
+	mu     sync.Mutex
+	out    io.Writer
+	buf    *bufio.Writer
+	closer io.Closer
+}
+
+// NewFileSink appends messages as JSON lines to the file at path
+func NewFileSink(path string) (kafka.EventSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{out: f, buf: bufio.NewWriter(f), closer: f}, nil
+}
+
+// NewStdoutSink writes messages as JSON lines to standard output
+func NewStdoutSink() kafka.EventSink {
+	return &fileSink{out: os.Stdout, buf: bufio.NewWriter(os.Stdout)}
+}
+
+func (fs *fileSink) Write(_ context.Context, messages []kafka.Message) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	now := time.Now()
+	enc := json.NewEncoder(fs.buf)
+	for _, msg := range messages {
+		record := fileRecord{
+			Topic:   msg.Topic,
+			Key:     msg.Key,
+			Headers: msg.Headers,
+			Time:    msg.Time,
+		}
+		if record.Time.IsZero() {
+			record.Time = now
+		}
+		if msg.Headers[kafka.ContentTypeHeader] != kafka.ProtobufContentType && json.Valid(msg.Value) {
+			record.Value = msg.Value
+		} else {
+			record.Binary = msg.Value
+		}
+
+		if err := enc.Encode(&record); err != nil {
+			return err
+		}
+	}
+
+	return fs.buf.Flush()
+}
+
+func (fs *fileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.buf.Flush(); err != nil {
+		return err
+	}
+
+	if f, ok := fs.out.(*os.File); ok && fs.closer != nil {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+
+	if fs.closer == nil {
+		return nil
+	}
+	return fs.closer.Close()
+}
@@ -0,0 +1,152 @@
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"shortbin/pkg/kafka"
+	"shortbin/pkg/logger"
+)
+
+const (
+	defaultBatchSize    = 500
+	defaultPollInterval = time.Second
+	maxBackoff          = 30 * time.Second
+)
+
+type postgresSink struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresSink inserts messages into the click_events table, for
+// deployments without a Kafka broker. PostgresConsumer reads them back.
+func NewPostgresSink(db *pgxpool.Pool) kafka.EventSink {
+	return &postgresSink{db: db}
+}
+
+func (ps *postgresSink) Write(ctx context.Context, messages []kafka.Message) error {
+	now := time.Now()
+	rows := make([][]interface{}, len(messages))
+	for i, msg := range messages {
+		createdAt := msg.Time
+		if createdAt.IsZero() {
+			createdAt = now
+		}
+		rows[i] = []interface{}{msg.Topic, msg.Key, msg.Value, msg.Headers, createdAt}
+	}
+
+	_, err := ps.db.CopyFrom(
+		ctx,
+		pgx.Identifier{"click_events"},
+		[]string{"topic", "key", "value", "headers", "created_at"},
+		pgx.CopyFromRows(rows),
+	)
+	return err
+}
+
+// Close is a no-op, the pool is owned by the caller
+func (ps *postgresSink) Close() error {
+	return nil
+}
+
+// PostgresConsumer is a kafka.IKafkaConsumer reading the messages written by
+// the Postgres sink, in insertion order
+type PostgresConsumer struct {
+	db     *pgxpool.Pool
+	topics []string
+}
+
+func NewPostgresConsumer(db *pgxpool.Pool, topics []string) *PostgresConsumer {
+	return &PostgresConsumer{
+		db:     db,
+		topics: topics,
+	}
+}
+
+// Consume handles messages until ctx is done. While the handler or the
+// database fail the batch is retried, backing off exponentially, so that no
+// message is skipped.
+func (pc *PostgresConsumer) Consume(ctx context.Context, handle kafka.HandlerFunc) error {
+	var wait time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+
+		consumed, err := pc.consumeBatch(ctx, handle)
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil
+			}
+			if wait = max(2*wait, defaultPollInterval); wait > maxBackoff {
+				wait = maxBackoff
+			}
+			logger.Errorf("failed to consume click events, retrying in %s: %v", wait, err)
+		case consumed == defaultBatchSize:
+			// more messages are waiting, continue right away
+			wait = 0
+		default:
+			wait = defaultPollInterval
+		}
+	}
+}
+
+// consumeBatch handles the oldest messages and deletes them in the same
+// transaction, rows are locked so that replicas consume disjoint batches. A
+// failing batch is handled again in full, so handlers must be idempotent.
+func (pc *PostgresConsumer) consumeBatch(ctx context.Context, handle kafka.HandlerFunc) (int, error) {
+	var consumed int
+	err := pgx.BeginFunc(ctx, pc.db, func(tx pgx.Tx) error {
+		query := `SELECT id, topic, key, value, headers, created_at FROM click_events
+			WHERE topic = ANY($1) ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED`
+		rows, err := tx.Query(ctx, query, pc.topics, defaultBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var ids []int64
+		var messages []kafka.Message
+		for rows.Next() {
+			var id int64
+			var msg kafka.Message
+			if err = rows.Scan(&id, &msg.Topic, &msg.Key, &msg.Value, &msg.Headers, &msg.Time); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			messages = append(messages, msg)
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			if err = handle(ctx, msg); err != nil {
+				return err
+			}
+		}
+
+		if len(ids) > 0 {
+			if _, err = tx.Exec(ctx, `DELETE FROM click_events WHERE id = ANY($1)`, ids); err != nil {
+				return err
+			}
+		}
+
+		consumed = len(ids)
+		return nil
+	})
+
+	return consumed, err
+}
+
+// Close is a no-op, the pool is owned by the caller
+func (pc *PostgresConsumer) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package sink holds the kafka.EventSink implementations for deployments
+// without a Kafka broker
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"shortbin/pkg/kafka"
+)
+
+const (
+	Kafka    = "kafka"
+	Postgres = "postgres"
+	File     = "file"
+	Stdout   = "stdout"
+	Noop     = "noop"
+)
+
+// Config selects and configures an EventSink
+type Config struct {
+	Type         string
+	Broker       string
+	BatchSize    int
+	BatchTimeout time.Duration
+	FilePath     string
+}
+
+// New EventSink for the configured type, defaults to Kafka. db is only used
+// by the Postgres sink.
+func New(cfg Config, db *pgxpool.Pool) (kafka.EventSink, error) {
+	switch cfg.Type {
+	case "", Kafka:
+		return kafka.NewKafkaSink(cfg.Broker, cfg.BatchSize, cfg.BatchTimeout), nil
+	case Postgres:
+		if db == nil {
+			return nil, errors.New("postgres event sink needs a postgres data source")
+		}
+		return NewPostgresSink(db), nil
+	case File:
+		return NewFileSink(cfg.FilePath)
+	case Stdout:
+		return NewStdoutSink(), nil
+	case Noop:
+		return NewNoopSink(), nil
+	default:
+		return nil, fmt.Errorf("unknown event sink: %s", cfg.Type)
+	}
+}
+
+type noopSink struct{}
+
+// NewNoopSink discards every message
+func NewNoopSink() kafka.EventSink {
+	return noopSink{}
+}
+
+func (noopSink) Write(context.Context, []kafka.Message) error {
+	return nil
+}
+
+func (noopSink) Close() error {
+	return nil
+}
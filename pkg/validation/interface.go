@@ -1,6 +1,12 @@
 package validation
 
+import (
+	"context"
+)
+
 // Validation interface
 type Validation interface {
 	ValidateStruct(s interface{}) error
+	// ValidateStructCtx validates with ctx, e.g. one from ResolveHosts
+	ValidateStructCtx(ctx context.Context, s interface{}) error
 }
@@ -0,0 +1,246 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/net/idna"
+	"golang.org/x/sync/errgroup"
+
+	"shortbin/pkg/config"
+)
+
+const (
+	defaultMaxLongURLLength = 2048
+
+	// HostPolicyNone accepts any host, HostPolicyResolve requires the host to
+	// resolve and HostPolicyPublic additionally rejects hosts resolving to
+	// loopback, private or link local addresses
+	HostPolicyNone    = "none"
+	HostPolicyResolve = "resolve"
+	HostPolicyPublic  = "public"
+
+	resolveTimeout = 2 * time.Second
+	// maxParallelLookups bounds the lookups of ResolveHosts
+	maxParallelLookups = 16
+)
+
+var defaultSchemes = []string{"http", "https"}
+
+var errInvalidLongURL = errors.New("invalid long url")
+
+type resolvedHostsKey struct{}
+
+// Blocklist tells whether links to a host are not allowed
+type Blocklist interface {
+	IsBlocked(host string) bool
+}
+
+// NormalizeLongURL lower cases the scheme and host of raw and converts an
+// internationalized host to its punycode form, so that hosts are compared
+// and stored in one canonical form
+func NormalizeLongURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" || u.Opaque != "" {
+		return "", errInvalidLongURL
+	}
+
+	host, err := idna.Lookup.ToASCII(strings.TrimSuffix(u.Hostname(), "."))
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" {
+		host += ":" + port
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(host)
+	return u.String(), nil
+}
+
+// ResolveHosts looks up the hosts of rawURLs when the host policy needs them,
+// each host once and in parallel. Validating with the returned context uses
+// the results instead of looking up one URL after the other, as for bulk
+// creation.
+func ResolveHosts(ctx context.Context, rawURLs []string) context.Context {
+	policy := config.GetConfig().LongURL.HostPolicy
+	if policy == "" || policy == HostPolicyNone {
+		return ctx
+	}
+
+	seen := make(map[string]bool)
+	resolved := make(map[string][]netip.Addr)
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(maxParallelLookups)
+	for _, raw := range rawURLs {
+		host := longURLHost(raw)
+		if host == "" || seen[host] {
+			continue
+		}
+		if _, err := netip.ParseAddr(host); err == nil {
+			continue
+		}
+
+		seen[host] = true
+		g.Go(func() error {
+			addrs := lookupHost(ctx, host)
+			mu.Lock()
+			resolved[host] = addrs
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return context.WithValue(ctx, resolvedHostsKey{}, resolved)
+}
+
+// longURLHost is the normalized host of raw, empty when raw is invalid
+func longURLHost(raw string) string {
+	normalized, err := NormalizeLongURL(raw)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
+// requestHost is the host the request was served on, from the struct field
+// named by the longurl parameter, e.g. longurl=RequestHost. A long URL
+// pointing there would redirect in a loop.
+func requestHost(fl validator.FieldLevel) string {
+	if fl.Param() == "" {
+		return ""
+	}
+
+	field := reflect.Indirect(fl.Parent()).FieldByName(fl.Param())
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+
+	host := field.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
+
+// validLongURL checks raw against the long_url policy in config: length,
+// scheme allowlist, no credentials, not our own host, not blocklisted and the
+// host resolution policy. Our own hosts are those configured and the host of
+// the request, when known.
+func validLongURL(ctx context.Context, raw string, requestHost string, blocklist Blocklist) bool {
+	cfg := config.GetConfig().LongURL
+
+	maxLength := cfg.MaxLength
+	if maxLength <= 0 {
+		maxLength = defaultMaxLongURLLength
+	}
+	if len(raw) > maxLength {
+		return false
+	}
+
+	normalized, err := NormalizeLongURL(raw)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return false
+	}
+
+	schemes := cfg.Schemes
+	if len(schemes) == 0 {
+		schemes = defaultSchemes
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		return false
+	}
+
+	// https://trusted.com@evil.com style links only serve to mislead
+	if u.User != nil {
+		return false
+	}
+
+	host := u.Hostname()
+	if host == requestHost {
+		return false
+	}
+	for _, own := range cfg.OwnHosts {
+		if host == strings.ToLower(own) {
+			return false
+		}
+	}
+
+	if blocklist != nil && blocklist.IsBlocked(host) {
+		return false
+	}
+
+	return allowedHost(ctx, host, cfg.HostPolicy)
+}
+
+func allowedHost(ctx context.Context, host string, policy string) bool {
+	if policy == "" || policy == HostPolicyNone {
+		return true
+	}
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		resolved, ok := ctx.Value(resolvedHostsKey{}).(map[string][]netip.Addr)[host]
+		if !ok {
+			resolved = lookupHost(ctx, host)
+		}
+		if len(resolved) == 0 {
+			return false
+		}
+		addrs = resolved
+	}
+
+	if policy == HostPolicyPublic {
+		for _, addr := range addrs {
+			addr = addr.Unmap()
+			if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+				addr.IsLinkLocalMulticast() || addr.IsUnspecified() || addr.IsMulticast() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// lookupHost returns the addresses of host, none when it does not resolve
+func lookupHost(ctx context.Context, host string) []netip.Addr {
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+
+	return addrs
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"context"
 	"github.com/go-playground/validator/v10"
 	"reflect"
 	"strings"
@@ -20,6 +21,7 @@ type option struct {
 	validator *validator.Validate
 	uni       *ut.UniversalTranslator
 	trans     *ut.Translator
+	blocklist Blocklist
 }
 
 type optionFn func(*option)
@@ -42,6 +44,13 @@ func WithUniversalTranslator(uni *ut.UniversalTranslator) Option {
 	})
 }
 
+// WithBlocklist set the domain Blocklist checked by the longurl validation
+func WithBlocklist(blocklist Blocklist) Option {
+	return optionFn(func(opt *option) {
+		opt.blocklist = blocklist
+	})
+}
+
 // WithTranslator set Translator
 func WithTranslator(trans *ut.Translator) Option {
 	return optionFn(func(opt *option) {
@@ -75,6 +84,13 @@ func getDefaultOption() *option {
 		return len(fl.Field().String()) >= 6
 	})
 
+	_ = v.RegisterTranslation("longurl", trans, func(ut ut.Translator) error {
+		return ut.Add("longurl", "{0} is not an allowed URL", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("longurl", fe.Field())
+		return t
+	})
+
 	_ = v.RegisterTranslation("countryCode", trans, func(ut ut.Translator) error {
 		return ut.Add("countryCode", "{0} must be at least 2 characters and start with '+'", true)
 	}, func(ut ut.Translator, fe validator.FieldError) string {
@@ -120,5 +136,11 @@ func getOption(opts ...Option) *option {
 		o.apply(opt)
 	}
 
+	// registered last as it depends on the blocklist option
+	blocklist := opt.blocklist
+	_ = opt.validator.RegisterValidationCtx("longurl", func(ctx context.Context, fl validator.FieldLevel) bool {
+		return validLongURL(ctx, fl.Field().String(), requestHost(fl), blocklist)
+	})
+
 	return opt
 }
@@ -1,7 +1,8 @@
 package validation
 
 import (
-	"fmt"
+	"context"
+	"errors"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -28,9 +29,44 @@ func (v *validation) ValidateStruct(s interface{}) error {
 	return nil
 }
 
+func (v *validation) ValidateStructCtx(ctx context.Context, s interface{}) error {
+	err := v.validator.StructCtx(ctx, s)
+	if err != nil {
+		return v.Translate(err)
+	}
+
+	return nil
+}
+
 func (v *validation) Translate(err error) error {
-	for _, e := range err.(validator.ValidationErrors) {
-		return fmt.Errorf("%s", e.Translate(*v.trans))
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	for _, e := range errs {
+		return &Error{message: e.Translate(*v.trans), errs: errs}
 	}
 	return err
 }
+
+// Error is a failed validation, its message is the translation of the first
+// failure
+type Error struct {
+	message string
+	errs    validator.ValidationErrors
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.errs
+}
+
+// IsInvalid reports whether err is a failed validation rather than a failure
+// of the validated operation
+func IsInvalid(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
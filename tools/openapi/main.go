@@ -0,0 +1,25 @@
+// Command openapi writes the OpenAPI 3 spec of the HTTP API, run by go
+// generate in internal/docs. It is a module of its own, so that swag and its
+// dependencies do not raise the versions shortbin is built with.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	root := flag.String("root", ".", "module root")
+	out := flag.String("o", "openapi.json", "output file")
+	flag.Parse()
+
+	spec, err := generate(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(*out, spec, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/swaggo/swag"
+)
+
+// mainFile holds the general API annotations, relative to the first of
+// searchDirs
+const mainFile = "main.go"
+
+// searchDirs, relative to the module root, hold every annotated handler and
+// the types their annotations refer to. swag resolves types by the package
+// of each directory, so the packages below internal are listed one by one.
+var searchDirs = []string{"cmd/api", "internal/...", "pkg/response"}
+
+// generate the OpenAPI 3 spec of the HTTP API from the swag annotations of
+// the handlers in the module at root, as indented JSON
+func generate(root string) ([]byte, error) {
+	var dirs []string
+	for _, dir := range searchDirs {
+		if tree, ok := strings.CutSuffix(dir, "/..."); ok {
+			packages, err := packageDirs(filepath.Join(root, tree))
+			if err != nil {
+				return nil, err
+			}
+			dirs = append(dirs, packages...)
+			continue
+		}
+		dirs = append(dirs, filepath.Join(root, dir))
+	}
+
+	parser := swag.New(swag.SetStrict(true), swag.SetDebugger(log.New(io.Discard, "", 0)))
+	if err := parser.ParseAPIMultiSearchDir(dirs, mainFile, 100); err != nil {
+		return nil, fmt.Errorf("parsing annotations: %w", err)
+	}
+
+	// swag only writes Swagger 2.0, which is converted to OpenAPI 3
+	v2, err := json.Marshal(parser.GetSwagger())
+	if err != nil {
+		return nil, err
+	}
+
+	var doc openapi2.T
+	if err = json.Unmarshal(v2, &doc); err != nil {
+		return nil, err
+	}
+
+	forms := splitForms(&doc)
+	v3, err := openapi2conv.ToV3(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("converting to OpenAPI 3: %w", err)
+	}
+	addForms(v3, forms)
+
+	spec, err := json.MarshalIndent(v3, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(spec, '\n'), nil
+}
+
+// operation is the path and method of an operation
+type operation struct {
+	path   string
+	method string
+}
+
+// splitForms takes the form parameters off operations taking a body as well,
+// like bulk create taking JSON or a CSV upload, which Swagger 2.0 does not
+// allow and openapi2conv refuses
+func splitForms(doc *openapi2.T) map[operation]openapi2.Parameters {
+	forms := map[operation]openapi2.Parameters{}
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			var hasBody bool
+			var form, rest openapi2.Parameters
+			for _, param := range op.Parameters {
+				switch param.In {
+				case "formData":
+					form = append(form, param)
+					continue
+				case "body":
+					hasBody = true
+				}
+				rest = append(rest, param)
+			}
+
+			if hasBody && len(form) > 0 {
+				op.Parameters = rest
+				forms[operation{path: path, method: method}] = form
+			}
+		}
+	}
+
+	return forms
+}
+
+// addForms puts the form parameters taken off by splitForms back, as the
+// multipart/form-data content of the request body
+func addForms(doc *openapi3.T, forms map[operation]openapi2.Parameters) {
+	for op, params := range forms {
+		schema := openapi3.NewObjectSchema()
+		for _, param := range params {
+			property := &openapi3.Schema{Type: param.Type, Format: param.Format, Description: param.Description}
+			if param.Type.Is("file") {
+				property.Type = &openapi3.Types{openapi3.TypeString}
+				property.Format = "binary"
+			}
+			schema.WithProperty(param.Name, property)
+			if param.Required {
+				schema.Required = append(schema.Required, param.Name)
+			}
+		}
+
+		body := doc.Paths.Value(op.path).GetOperation(op.method).RequestBody.Value
+		body.Content["multipart/form-data"] = openapi3.NewMediaType().WithSchema(schema)
+	}
+}
+
+// packageDirs returns dir and every directory below it holding Go files
+func packageDirs(dir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+		if parent := filepath.Dir(path); !slices.Contains(dirs, parent) {
+			dirs = append(dirs, parent)
+		}
+		return nil
+	})
+
+	return dirs, err
+}